@@ -0,0 +1,110 @@
+package microerror
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+var benchmarkDepths = []int{1, 10, 100}
+
+var (
+	benchmarkErr    error
+	benchmarkString string
+	benchmarkBool   bool
+)
+
+func newBenchmarkError(depth int) error {
+	err := Maskf(testMicroErr, "test annotation")
+	for i := 1; i < depth; i++ {
+		err = Mask(err)
+	}
+
+	return err
+}
+
+// BenchmarkMask measures masking errors.
+//
+// Compare results before and after a change with benchstat:
+//
+//	go test -run none -bench . -count 10 > old.txt
+//	go test -run none -bench . -count 10 > new.txt
+//	benchstat old.txt new.txt
+func BenchmarkMask(b *testing.B) {
+	for _, depth := range benchmarkDepths {
+		b.Run("depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err := error(testMicroErr)
+				for j := 0; j < depth; j++ {
+					err = Mask(err)
+				}
+				benchmarkErr = err
+			}
+		})
+	}
+}
+
+func BenchmarkMaskf(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchmarkErr = Maskf(testMicroErr, "test annotation %d", i)
+	}
+}
+
+func BenchmarkJSON(b *testing.B) {
+	for _, depth := range benchmarkDepths {
+		err := newBenchmarkError(depth)
+		b.Run("depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				benchmarkString = JSON(err)
+			}
+		})
+	}
+}
+
+func BenchmarkPretty(b *testing.B) {
+	for _, depth := range benchmarkDepths {
+		err := newBenchmarkError(depth)
+		b.Run("depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				benchmarkString = Pretty(err, true)
+			}
+		})
+	}
+}
+
+func BenchmarkErrorsIs(b *testing.B) {
+	for _, depth := range benchmarkDepths {
+		err := newBenchmarkError(depth)
+		b.Run("depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				benchmarkBool = errors.Is(err, testMicroErr)
+			}
+		})
+	}
+}
+
+// Test_Mask_Allocs guards the number of allocations BenchmarkMask reports.
+func Test_Mask_Allocs(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		benchmarkErr = Mask(testMicroErr)
+	})
+	if allocs != 1 {
+		t.Fatalf("expected Mask to allocate once, got %v allocations", allocs)
+	}
+}
+
+// Test_Maskf_Allocs makes sure Maskf only allocates the annotation, the
+// annotatedError and the stackedError when no Catalog is set.
+func Test_Maskf_Allocs(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		benchmarkErr = Maskf(testMicroErr, "test annotation %d", 42)
+	})
+	if allocs != 3 {
+		t.Fatalf("expected Maskf to allocate 3 times, got %v allocations", allocs)
+	}
+}
@@ -0,0 +1,424 @@
+package microerror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Message is the translation of a single error kind.
+type Message struct {
+	// Title replaces the message generated from Error.Kind.
+	Title string `json:"title"`
+	// Desc replaces Error.Desc.
+	Desc string `json:"desc,omitempty"`
+	// Annotations maps annotation formats as passed to Maskf to their
+	// translated formats. Arguments are rendered with the verbs of the
+	// original format when the error is created, so verbs of translated
+	// formats only pick the argument, e.g. %[2]d picks the second one.
+	// Annotations are only translated for errors created while a Catalog
+	// is set with SetCatalog.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Localized is an error rendered in a specific language.
+type Localized struct {
+	Title      string
+	Desc       string
+	Annotation string
+}
+
+func (l Localized) String() string {
+	if l.Annotation == "" {
+		return l.Title
+	}
+	if l.Title == "" {
+		return l.Annotation
+	}
+	return l.Title + delimiter + l.Annotation
+}
+
+// catalogFile is the format of the files read by Catalog.Load.
+type catalogFile struct {
+	Fallback []string           `json:"fallback,omitempty"`
+	Messages map[string]Message `json:"messages"`
+}
+
+// Catalog holds translations of error kinds keyed by language and
+// Error.Kind. A Catalog must be fully populated before it is used
+// concurrently.
+type Catalog struct {
+	fallbacks map[string][]string
+	messages  map[string]map[string]Message
+}
+
+func NewCatalog() *Catalog {
+	return &Catalog{
+		fallbacks: map[string][]string{},
+		messages:  map[string]map[string]Message{},
+	}
+}
+
+// LoadCatalog creates a Catalog and loads all translation files found in dir
+// of fsys into it. See Catalog.Load for the file format.
+func LoadCatalog(fsys fs.FS, dir string) (*Catalog, error) {
+	c := NewCatalog()
+
+	err := c.Load(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Load reads all *.json files in dir of fsys, which is usually an embed.FS.
+// The language of each file is its base name, e.g. "de-AT.json" contains
+// Austrian German translations. Files look like this:
+//
+//	{
+//		"fallback": ["de", "en"],
+//		"messages": {
+//			"invalidConfigError": {
+//				"title": "ungültige Konfiguration",
+//				"desc": "Die Konfiguration ist ungültig.",
+//				"annotations": {
+//					"%s must not be empty": "%s darf nicht leer sein"
+//				}
+//			}
+//		}
+//	}
+func (c *Catalog) Load(fsys fs.FS, dir string) error {
+	matches, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("microerror.Catalog.Load: %w", err)
+	}
+
+	for _, m := range matches {
+		bytes, err := fs.ReadFile(fsys, m)
+		if err != nil {
+			return fmt.Errorf("microerror.Catalog.Load: %w", err)
+		}
+
+		var f catalogFile
+		err = json.Unmarshal(bytes, &f)
+		if err != nil {
+			return fmt.Errorf("microerror.Catalog.Load: %w file=%#q", err, m)
+		}
+
+		lang := strings.TrimSuffix(path.Base(m), ".json")
+		for kind, msg := range f.Messages {
+			c.Add(lang, kind, msg)
+		}
+		if len(f.Fallback) > 0 {
+			c.SetFallback(lang, f.Fallback...)
+		}
+	}
+
+	return nil
+}
+
+// Add adds the translation of kind to lang.
+func (c *Catalog) Add(lang string, kind string, m Message) {
+	if c.messages[lang] == nil {
+		c.messages[lang] = map[string]Message{}
+	}
+	c.messages[lang][kind] = m
+}
+
+// SetFallback sets the languages tried in order when a kind is not
+// translated to lang. Fallback languages follow their own fallbacks. Without
+// an explicit fallback the parent tag of lang is tried, e.g. "de" for "de-AT".
+func (c *Catalog) SetFallback(lang string, fallbacks ...string) {
+	c.fallbacks[lang] = fallbacks
+}
+
+// Languages returns all languages having at least one translation, sorted.
+func (c *Catalog) Languages() []string {
+	var langs []string
+	for lang := range c.messages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	return langs
+}
+
+// Lookup finds the translation of kind for lang following the fallback
+// chain of lang.
+func (c *Catalog) Lookup(kind string, lang string) (Message, bool) {
+	for _, l := range c.chain(lang) {
+		m, ok := c.messages[l][kind]
+		if ok {
+			return m, true
+		}
+	}
+
+	return Message{}, false
+}
+
+// Localize renders err in lang. Every part is looked up separately along the
+// fallback chain of lang, so a language may translate only some of them.
+// Parts which are not translated at all fall back to what Error() and
+// Error.Desc return.
+func (c *Catalog) Localize(err error, lang string) Localized {
+	var l Localized
+
+	if err == nil {
+		l.Title = c.find(kindNil, lang, title)
+		if l.Title == "" {
+			l.Title = toStringCase(kindNil)
+		}
+		return l
+	}
+
+	var eerr *Error
+	if !errors.As(err, &eerr) {
+		l.Title = c.find(kindUnknown, lang, title)
+		l.Desc = c.find(kindUnknown, lang, desc)
+		l.Annotation = err.Error()
+		return l
+	}
+
+	l.Title = c.find(eerr.Kind, lang, title)
+	if l.Title == "" {
+		l.Title = eerr.Error()
+	}
+	l.Desc = c.find(eerr.Kind, lang, desc)
+	if l.Desc == "" {
+		l.Desc = eerr.Desc
+	}
+
+	var aerr *annotatedError
+	if errors.As(err, &aerr) {
+		l.Annotation = aerr.annotation
+
+		// The format is only retained while a Catalog is set, see
+		// SetCatalog.
+		if aerr.format != "" {
+			f := c.find(eerr.Kind, lang, func(m Message) string { return m.Annotations[aerr.format] })
+			if f != "" {
+				l.Annotation = formatArgs(f, aerr.args)
+			}
+		}
+	}
+
+	return l
+}
+
+// Missing returns the sorted kinds of errs which have no title translated
+// directly to lang. Fallback languages are not considered. It is meant to be
+// used in tests to make sure all kinds of a package are translated.
+func (c *Catalog) Missing(lang string, errs ...*Error) []string {
+	seen := map[string]bool{}
+
+	var missing []string
+	for _, e := range errs {
+		if seen[e.Kind] {
+			continue
+		}
+		seen[e.Kind] = true
+
+		m, ok := c.messages[lang][e.Kind]
+		if !ok || m.Title == "" {
+			missing = append(missing, e.Kind)
+		}
+	}
+	sort.Strings(missing)
+
+	return missing
+}
+
+// find returns the first non-empty part picked from the translations of kind
+// along the fallback chain of lang.
+func (c *Catalog) find(kind string, lang string, pick func(m Message) string) string {
+	for _, l := range c.chain(lang) {
+		m, ok := c.messages[l][kind]
+		if !ok {
+			continue
+		}
+		s := pick(m)
+		if s != "" {
+			return s
+		}
+	}
+
+	return ""
+}
+
+func (c *Catalog) chain(lang string) []string {
+	var chain []string
+	seen := map[string]bool{}
+
+	var walk func(l string)
+	walk = func(l string) {
+		if seen[l] {
+			return
+		}
+		seen[l] = true
+		chain = append(chain, l)
+
+		fallbacks, ok := c.fallbacks[l]
+		if !ok {
+			i := strings.LastIndex(l, "-")
+			if i > 0 {
+				fallbacks = []string{l[:i]}
+			}
+		}
+		for _, f := range fallbacks {
+			walk(f)
+		}
+	}
+	walk(lang)
+
+	return chain
+}
+
+func title(m Message) string { return m.Title }
+func desc(m Message) string  { return m.Desc }
+
+var defaultCatalog = NewCatalog()
+
+// localizeAnnotations enables retaining the format and the rendered
+// arguments of annotations, see SetCatalog.
+var localizeAnnotations bool
+
+// SetCatalog sets the Catalog used by Localize. Setting a Catalog also
+// makes Maskf, MaskfSkip and Wrapf retain what is needed to translate
+// annotations, which renders their arguments a second time. Passing nil
+// restores the empty default Catalog and disables translating annotations.
+// It is meant to be called during program initialization.
+func SetCatalog(c *Catalog) {
+	localizeAnnotations = c != nil
+	if c == nil {
+		c = NewCatalog()
+	}
+	defaultCatalog = c
+}
+
+// Localize renders err in lang using the Catalog set with SetCatalog.
+func Localize(err error, lang string) Localized {
+	return defaultCatalog.Localize(err, lang)
+}
+
+// argVerb is a verb of a format string like "%5.2f" or "%[2]s".
+type argVerb struct {
+	// start and end are the offsets of the verb in the format string.
+	start, end int
+	// spec is the verb without its explicit argument index.
+	spec string
+	// arg is the index of the argument the verb renders.
+	arg int
+}
+
+// parseVerbs returns the verbs of the format string f. It returns false for
+// formats using '*' for width or precision, which consume arguments of their
+// own.
+func parseVerbs(f string) ([]argVerb, bool) {
+	var verbs []argVerb
+	var arg int
+	for i := 0; i < len(f); {
+		if f[i] != '%' {
+			i++
+			continue
+		}
+		if i+1 < len(f) && f[i+1] == '%' {
+			i += 2
+			continue
+		}
+
+		v := argVerb{start: i}
+		j := i + 1
+		for j < len(f) && strings.IndexByte("+-# 0", f[j]) >= 0 {
+			j++
+		}
+		spec := "%" + f[i+1:j]
+		if j < len(f) && f[j] == '[' {
+			k := strings.IndexByte(f[j:], ']')
+			if k < 0 {
+				return nil, false
+			}
+			n, err := strconv.Atoi(f[j+1 : j+k])
+			if err != nil || n < 1 {
+				return nil, false
+			}
+			arg = n - 1
+			j += k + 1
+		}
+		k := j
+		for k < len(f) && (f[k] == '.' || '0' <= f[k] && f[k] <= '9') {
+			k++
+		}
+		if k >= len(f) || f[k] == '*' || f[k] == '[' {
+			return nil, false
+		}
+		_, size := utf8.DecodeRuneInString(f[k:])
+
+		v.end = k + size
+		v.spec = spec + f[j:v.end]
+		v.arg = arg
+		verbs = append(verbs, v)
+
+		arg++
+		i = v.end
+	}
+
+	return verbs, true
+}
+
+// renderArgs renders every argument of v with the first verb of f using
+// it. Arguments without a verb are rendered with %v. This way annotations
+// can be localized without retaining the arguments themselves.
+func renderArgs(f string, v []interface{}) []string {
+	if len(v) == 0 {
+		return nil
+	}
+
+	args := make([]string, len(v))
+	rendered := make([]bool, len(v))
+	verbs, _ := parseVerbs(f)
+	for _, verb := range verbs {
+		if verb.arg < len(v) && !rendered[verb.arg] {
+			args[verb.arg] = fmt.Sprintf(verb.spec, v[verb.arg])
+			rendered[verb.arg] = true
+		}
+	}
+	for i := range v {
+		if !rendered[i] {
+			args[i] = fmt.Sprint(v[i])
+		}
+	}
+
+	return args
+}
+
+// formatArgs renders the format string f with arguments rendered by
+// renderArgs. Verbs only pick the argument they are replaced with.
+func formatArgs(f string, args []string) string {
+	verbs, ok := parseVerbs(f)
+	if !ok {
+		return f
+	}
+
+	var b strings.Builder
+	var last int
+	for _, verb := range verbs {
+		b.WriteString(strings.ReplaceAll(f[last:verb.start], "%%", "%"))
+		if verb.arg < len(args) {
+			b.WriteString(args[verb.arg])
+		} else {
+			r, _ := utf8.DecodeLastRuneInString(verb.spec)
+			b.WriteString("%!" + string(r) + "(MISSING)")
+		}
+		last = verb.end
+	}
+	b.WriteString(strings.ReplaceAll(f[last:], "%%", "%"))
+
+	return b.String()
+}
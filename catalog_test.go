@@ -0,0 +1,212 @@
+package microerror
+
+import (
+	"embed"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+//go:embed testdata/catalog
+var testCatalogFS embed.FS
+
+func Test_Catalog_Localize(t *testing.T) {
+	catalog, err := LoadCatalog(testCatalogFS, "testdata/catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetCatalog(catalog)
+	defer SetCatalog(nil)
+
+	testCases := []struct {
+		name              string
+		inputErrorFunc    func() error
+		lang              string
+		expectedLocalized Localized
+	}{
+		{
+			name: "case 0: nil",
+			inputErrorFunc: func() error {
+				return nil
+			},
+			lang: "de",
+			expectedLocalized: Localized{
+				Title: "nil",
+			},
+		},
+		{
+			name: "case 1: error=errors.New falls back to unknown kind",
+			inputErrorFunc: func() error {
+				return Mask(errors.New("test error"))
+			},
+			lang: "de",
+			expectedLocalized: Localized{
+				Title:      "unknown error",
+				Annotation: "test error",
+			},
+		},
+		{
+			name: "case 2: error=microerror.Error translated",
+			inputErrorFunc: func() error {
+				return Mask(testMicroErr)
+			},
+			lang: "de",
+			expectedLocalized: Localized{
+				Title: "Testfehler",
+				Desc:  "Beim Testen ist etwas schiefgelaufen.",
+			},
+		},
+		{
+			name: "case 3: Maskf with translated annotation",
+			inputErrorFunc: func() error {
+				return Maskf(testMicroErr, "%s must not be empty", "config.Name")
+			},
+			lang: "de",
+			expectedLocalized: Localized{
+				Title:      "Testfehler",
+				Desc:       "Beim Testen ist etwas schiefgelaufen.",
+				Annotation: "config.Name darf nicht leer sein",
+			},
+		},
+		{
+			name: "case 4: Maskf with reordered arguments",
+			inputErrorFunc: func() error {
+				return Mask(Maskf(testMicroErr, "%s is not %s", "a", "b"))
+			},
+			lang: "de",
+			expectedLocalized: Localized{
+				Title:      "Testfehler",
+				Desc:       "Beim Testen ist etwas schiefgelaufen.",
+				Annotation: "b ist nicht a",
+			},
+		},
+		{
+			name: "case 5: Maskf with untranslated annotation",
+			inputErrorFunc: func() error {
+				return Maskf(testMicroErr, "test annotation")
+			},
+			lang: "de",
+			expectedLocalized: Localized{
+				Title:      "Testfehler",
+				Desc:       "Beim Testen ist etwas schiefgelaufen.",
+				Annotation: "test annotation",
+			},
+		},
+		{
+			name: "case 6: region falls back to parent language per part",
+			inputErrorFunc: func() error {
+				return Maskf(testMicroErr, "%s must not be empty", "config.Name")
+			},
+			lang: "de-AT",
+			expectedLocalized: Localized{
+				Title:      "Testfehler (AT)",
+				Desc:       "Beim Testen ist etwas schiefgelaufen.",
+				Annotation: "config.Name darf nicht leer sein",
+			},
+		},
+		{
+			name: "case 7: unknown language falls back to error fields",
+			inputErrorFunc: func() error {
+				return Maskf(testMicroErr, "test annotation")
+			},
+			lang: "fr",
+			expectedLocalized: Localized{
+				Title:      "test kind",
+				Desc:       "test-desc",
+				Annotation: "test annotation",
+			},
+		},
+		{
+			name: "case 8: explicit fallback chain",
+			inputErrorFunc: func() error {
+				return Mask(errors.New("test error"))
+			},
+			lang: "de-CH",
+			expectedLocalized: Localized{
+				Title:      "unknown error",
+				Annotation: "test error",
+			},
+		},
+		{
+			name: "case 9: translated verbs only pick arguments rendered by original verbs",
+			inputErrorFunc: func() error {
+				return Maskf(testMicroErr, "%d%% of %s failed after %.1fs", 50, "requests", 1.25)
+			},
+			lang: "de",
+			expectedLocalized: Localized{
+				Title:      "Testfehler",
+				Desc:       "Beim Testen ist etwas schiefgelaufen.",
+				Annotation: "requests nach 1.2s zu 50% fehlgeschlagen",
+			},
+		},
+		{
+			name: "case 10: arguments changed after masking are not rendered again",
+			inputErrorFunc: func() error {
+				var b strings.Builder
+				b.WriteString("a")
+				err := Maskf(testMicroErr, "%s is not %s", &b, "b")
+				b.WriteString("c")
+				return err
+			},
+			lang: "de",
+			expectedLocalized: Localized{
+				Title:      "Testfehler",
+				Desc:       "Beim Testen ist etwas schiefgelaufen.",
+				Annotation: "b ist nicht a",
+			},
+		},
+		{
+			name: "case 11: annotations of errors created without catalog are not translated",
+			inputErrorFunc: func() error {
+				SetCatalog(nil)
+				defer SetCatalog(catalog)
+
+				return Maskf(testMicroErr, "%s must not be empty", "config.Name")
+			},
+			lang: "de",
+			expectedLocalized: Localized{
+				Title:      "Testfehler",
+				Desc:       "Beim Testen ist etwas schiefgelaufen.",
+				Annotation: "config.Name must not be empty",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			localized := catalog.Localize(tc.inputErrorFunc(), tc.lang)
+			if !cmp.Equal(localized, tc.expectedLocalized) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedLocalized, localized))
+			}
+		})
+	}
+}
+
+func Test_Catalog_Missing(t *testing.T) {
+	catalog, err := LoadCatalog(testCatalogFS, "testdata/catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherMicroErr := &Error{
+		Kind: "otherKind",
+	}
+
+	// Every language must translate testMicroErr.
+	for _, lang := range catalog.Languages() {
+		missing := catalog.Missing(lang, testMicroErr)
+		if len(missing) != 0 {
+			t.Errorf("language %#q misses translations for %v", lang, missing)
+		}
+	}
+
+	missing := catalog.Missing("de", testMicroErr, otherMicroErr, otherMicroErr)
+	if !cmp.Equal(missing, []string{"otherKind"}) {
+		t.Fatalf("\n\n%s\n", cmp.Diff([]string{"otherKind"}, missing))
+	}
+}
@@ -0,0 +1,36 @@
+package microerror
+
+import "strings"
+
+var codesInMessages bool
+
+// SetCodesInMessages enables prefixing messages returned by Error() with
+// the code of the error, e.g. "[GS-1042] invalid config error". It is
+// disabled by default and meant to be called during program initialization.
+func SetCodesInMessages(enabled bool) {
+	codesInMessages = enabled
+}
+
+// ByCode returns the error registered with code in the default registry or
+// nil when there is none.
+func ByCode(code string) *Error {
+	return defaultRegistry.ByCode(code)
+}
+
+func codePrefix(code string) string {
+	return "[" + code + "] "
+}
+
+// cutCode removes the code prefix of msg if it has one.
+func cutCode(msg string) (string, string, bool) {
+	if !strings.HasPrefix(msg, "[") {
+		return "", msg, false
+	}
+
+	code, rest, ok := strings.Cut(msg[1:], "] ")
+	if !ok || code == "" {
+		return "", msg, false
+	}
+
+	return code, rest, true
+}
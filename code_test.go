@@ -0,0 +1,74 @@
+package microerror
+
+import (
+	"testing"
+)
+
+var testCodeMicroErr = &Error{
+	Code: "GS-1042",
+	Kind: "invalidConfigError",
+}
+
+func Test_Error_Code(t *testing.T) {
+	err := Maskf(testCodeMicroErr, "test annotation")
+	if err.Error() != "invalid config error: test annotation" {
+		t.Fatalf("expected %#q got %#q", "invalid config error: test annotation", err.Error())
+	}
+
+	SetCodesInMessages(true)
+	defer SetCodesInMessages(false)
+
+	if err.Error() != "[GS-1042] invalid config error: test annotation" {
+		t.Fatalf("expected %#q got %#q", "[GS-1042] invalid config error: test annotation", err.Error())
+	}
+	if Mask(testMicroErr).Error() != "test kind" {
+		t.Fatalf("expected %#q got %#q", "test kind", Mask(testMicroErr).Error())
+	}
+	if Pretty(err, false) != "[GS-1042] Invalid config: test annotation" {
+		t.Fatalf("expected %#q got %#q", "[GS-1042] Invalid config: test annotation", Pretty(err, false))
+	}
+	if Pretty(Mask(testCodeMicroErr), false) != "[GS-1042] Invalid config" {
+		t.Fatalf("expected %#q got %#q", "[GS-1042] Invalid config", Pretty(Mask(testCodeMicroErr), false))
+	}
+}
+
+func Test_Registry_Code(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(testCodeMicroErr, testMicroErr)
+
+	if r.ByCode("GS-1042") != testCodeMicroErr {
+		t.Fatalf("expected %#v got %#v", testCodeMicroErr, r.ByCode("GS-1042"))
+	}
+	if r.ByCode("GS-0000") != nil {
+		t.Fatalf("expected nil got %#v", r.ByCode("GS-0000"))
+	}
+
+	// Registering the same error again is fine.
+	err := r.Register(testCodeMicroErr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another error with the same code is not.
+	err = r.Register(&Error{Code: "GS-1042", Kind: "otherKind"})
+	if !IsInvalidRegistration(err) {
+		t.Fatalf("expected invalid registration error, got %v", err)
+	}
+	err = NewRegistry().Register(&Error{Code: "GS-1", Kind: "aKind"}, &Error{Code: "GS-1", Kind: "bKind"})
+	if !IsInvalidRegistration(err) {
+		t.Fatalf("expected invalid registration error, got %v", err)
+	}
+
+	SetCodesInMessages(true)
+	defer SetCodesInMessages(false)
+
+	// The code takes precedence over the kind words.
+	e, annotation, ok := r.ParseMessage("[GS-1042] renamed kind: test annotation")
+	if !ok || e != testCodeMicroErr || annotation != "test annotation" {
+		t.Fatalf("expected %#v, %#q got %#v, %#q", testCodeMicroErr, "test annotation", e, annotation)
+	}
+	e, _, ok = r.ParseMessage("[GS-0000] test kind")
+	if !ok || e != testMicroErr {
+		t.Fatalf("expected %#v got %#v", testMicroErr, e)
+	}
+}
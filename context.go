@@ -0,0 +1,152 @@
+package microerror
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ContextExtractor extracts a single value from ctx. It returns false when
+// ctx does not carry the value.
+type ContextExtractor func(ctx context.Context) (string, bool)
+
+type namedExtractor struct {
+	name      string
+	extractor ContextExtractor
+}
+
+var (
+	contextExtractorsMu sync.RWMutex
+	contextExtractors   []namedExtractor
+)
+
+// RegisterContextExtractor registers e so MaskCtx stores the value it
+// extracts under name. Registering a name again replaces its extractor.
+func RegisterContextExtractor(name string, e ContextExtractor) {
+	contextExtractorsMu.Lock()
+	defer contextExtractorsMu.Unlock()
+
+	for i, n := range contextExtractors {
+		if n.name == name {
+			contextExtractors[i].extractor = e
+			return
+		}
+	}
+	contextExtractors = append(contextExtractors, namedExtractor{name: name, extractor: e})
+}
+
+// ContextValue returns a ContextExtractor reading ctx.Value(key). Values
+// which are neither strings nor fmt.Stringers are formatted with %v.
+func ContextValue(key interface{}) ContextExtractor {
+	return func(ctx context.Context) (string, bool) {
+		v := ctx.Value(key)
+		switch v := v.(type) {
+		case nil:
+			return "", false
+		case string:
+			return v, v != ""
+		case fmt.Stringer:
+			return v.String(), true
+		default:
+			return fmt.Sprintf("%v", v), true
+		}
+	}
+}
+
+type traceparentKey struct{}
+
+// WithTraceparent returns a copy of ctx carrying the W3C traceparent header
+// value, e.g. "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01".
+// Invalid values are ignored.
+func WithTraceparent(ctx context.Context, traceparent string) context.Context {
+	if !isTraceparent(traceparent) {
+		return ctx
+	}
+	return context.WithValue(ctx, traceparentKey{}, traceparent)
+}
+
+// Traceparent is a ContextExtractor returning the W3C traceparent set with
+// WithTraceparent.
+func Traceparent(ctx context.Context) (string, bool) {
+	tp, ok := ctx.Value(traceparentKey{}).(string)
+	return tp, ok
+}
+
+// MaskCtx is like Mask but also stores the values extracted from ctx by all
+// extractors registered with RegisterContextExtractor. The values are
+// included in JSON output and returned by Fields.
+func MaskCtx(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	serr := mask(err)
+	serr.fields = extractFields(ctx)
+
+	return serr
+}
+
+// Fields returns the values stored by MaskCtx along the chain of err. When
+// the same name was extracted more than once the outermost value wins.
+func Fields(err error) map[string]string {
+	var fields map[string]string
+	walk(err, func(e error) bool {
+		serr, ok := e.(*stackedError)
+		if ok {
+			for k, v := range serr.fields {
+				if fields == nil {
+					fields = map[string]string{}
+				}
+				if _, ok := fields[k]; !ok {
+					fields[k] = v
+				}
+			}
+		}
+		return true
+	})
+
+	return fields
+}
+
+func extractFields(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+
+	contextExtractorsMu.RLock()
+	defer contextExtractorsMu.RUnlock()
+
+	var fields map[string]string
+	for _, n := range contextExtractors {
+		v, ok := n.extractor(ctx)
+		if !ok {
+			continue
+		}
+		if fields == nil {
+			fields = map[string]string{}
+		}
+		fields[n.name] = v
+	}
+
+	return fields
+}
+
+// isTraceparent validates the version-format of a W3C traceparent, see
+// https://www.w3.org/TR/trace-context/#traceparent-header-field-values.
+func isTraceparent(s string) bool {
+	if len(s) != 55 || s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return false
+	}
+
+	for i, r := range s {
+		if i == 2 || i == 35 || i == 52 {
+			continue
+		}
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+
+	// Version ff and all-zero trace and parent IDs are invalid.
+	return s[:2] != "ff" && s[3:35] != "00000000000000000000000000000000" && s[36:52] != "0000000000000000"
+}
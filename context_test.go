@@ -0,0 +1,112 @@
+package microerror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testRequestIDKey struct{}
+
+func Test_MaskCtx(t *testing.T) {
+	defer func(e []namedExtractor) { contextExtractors = e }(contextExtractors)
+	contextExtractors = nil
+
+	RegisterContextExtractor("request_id", ContextValue(testRequestIDKey{}))
+	RegisterContextExtractor("traceparent", Traceparent)
+
+	traceparent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	testCases := []struct {
+		name           string
+		inputErrorFunc func() error
+		expectedFields map[string]string
+	}{
+		{
+			name: "case 0: no context values",
+			inputErrorFunc: func() error {
+				return MaskCtx(context.Background(), testMicroErr)
+			},
+			expectedFields: nil,
+		},
+		{
+			name: "case 1: request ID and traceparent",
+			inputErrorFunc: func() error {
+				ctx := context.WithValue(context.Background(), testRequestIDKey{}, "req-1")
+				ctx = WithTraceparent(ctx, traceparent)
+				return MaskCtx(ctx, Maskf(testMicroErr, "test annotation"))
+			},
+			expectedFields: map[string]string{
+				"request_id":  "req-1",
+				"traceparent": traceparent,
+			},
+		},
+		{
+			name: "case 2: invalid traceparent is ignored",
+			inputErrorFunc: func() error {
+				ctx := WithTraceparent(context.Background(), "00-00000000000000000000000000000000-b7ad6b7169203331-01")
+				return MaskCtx(ctx, testMicroErr)
+			},
+			expectedFields: nil,
+		},
+		{
+			name: "case 3: outermost value wins and inner values are kept",
+			inputErrorFunc: func() error {
+				ctx := context.WithValue(context.Background(), testRequestIDKey{}, "req-1")
+				ctx = WithTraceparent(ctx, traceparent)
+				err := MaskCtx(ctx, errors.New("test error"))
+				err = Mask(err)
+				ctx = context.WithValue(context.Background(), testRequestIDKey{}, "req-2")
+				return MaskCtx(ctx, err)
+			},
+			expectedFields: map[string]string{
+				"request_id":  "req-2",
+				"traceparent": traceparent,
+			},
+		},
+		{
+			name: "case 4: values behind errors.Join",
+			inputErrorFunc: func() error {
+				ctx := context.WithValue(context.Background(), testRequestIDKey{}, "req-1")
+				err := MaskCtx(ctx, errors.New("test error"))
+				return Mask(errors.Join(errors.New("test error"), err))
+			},
+			expectedFields: map[string]string{
+				"request_id": "req-1",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := tc.inputErrorFunc()
+
+			fields := Fields(err)
+			if !cmp.Equal(fields, tc.expectedFields) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedFields, fields))
+			}
+
+			var o JSONError
+			jsonErr := json.Unmarshal([]byte(JSON(err)), &o)
+			if jsonErr != nil {
+				t.Fatal(jsonErr)
+			}
+			if !cmp.Equal(o.Fields, tc.expectedFields) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedFields, o.Fields))
+			}
+		})
+	}
+}
+
+func Test_MaskCtx_Nil(t *testing.T) {
+	err := MaskCtx(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+}
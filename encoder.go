@@ -0,0 +1,165 @@
+package microerror
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// EncoderConfig configures an Encoder created with NewEncoder.
+type EncoderConfig struct {
+	// Writer receives the encoded errors. It is required.
+	Writer io.Writer
+
+	// Indent indents documents with the given string when not empty.
+	// Documents are written on a single line otherwise.
+	Indent string
+	// OmitStack drops the stack of the error and all of its causes.
+	OmitStack bool
+	// Fields is the allowlist of top-level keys written, e.g. "desc",
+	// "annotation" or "stack". The required keys "kind" and
+	// "schema_version" are always written. All keys are written when it
+	// is nil.
+	Fields []string
+	// MaxAnnotationLength truncates annotations longer than the given
+	// number of runes when greater than zero.
+	MaxAnnotationLength int
+}
+
+// Encoder writes errors in the format rendered by JSON to an io.Writer.
+// Every document is followed by a newline so a sequence of errors written
+// without Indent is valid NDJSON.
+type Encoder struct {
+	encoder *json.Encoder
+
+	omitStack           bool
+	fields              map[string]bool
+	maxAnnotationLength int
+}
+
+func NewEncoder(config EncoderConfig) (*Encoder, error) {
+	if config.Writer == nil {
+		return nil, Maskf(invalidConfigError, "%T.Writer must not be empty", config)
+	}
+	if config.MaxAnnotationLength < 0 {
+		return nil, Maskf(invalidConfigError, "%T.MaxAnnotationLength must not be negative", config)
+	}
+
+	var fields map[string]bool
+	if config.Fields != nil {
+		fields = map[string]bool{}
+		for _, f := range config.Fields {
+			if _, ok := jsonKeys[f]; !ok {
+				return nil, Maskf(invalidConfigError, "%T.Fields must only contain keys of JSON documents, got %#q", config, f)
+			}
+			fields[f] = true
+		}
+	}
+
+	encoder := json.NewEncoder(config.Writer)
+	encoder.SetIndent("", config.Indent)
+
+	e := &Encoder{
+		encoder: encoder,
+
+		omitStack:           config.OmitStack,
+		fields:              fields,
+		maxAnnotationLength: config.MaxAnnotationLength,
+	}
+
+	return e, nil
+}
+
+// Encode writes err followed by a newline.
+func (e *Encoder) Encode(err error) error {
+	o := newJSONError(err)
+	e.apply(&o)
+
+	encodeErr := e.encoder.Encode(o)
+	if encodeErr != nil {
+		return Mask(encodeErr)
+	}
+
+	return nil
+}
+
+// EncodeAll writes every error of errs followed by a newline. It stops at
+// the first error returned by the underlying io.Writer.
+func (e *Encoder) EncodeAll(errs ...error) error {
+	for _, err := range errs {
+		encodeErr := e.Encode(err)
+		if encodeErr != nil {
+			return Mask(encodeErr)
+		}
+	}
+
+	return nil
+}
+
+// apply applies the options of e to o. Nested values are copied before
+// they are changed.
+func (e *Encoder) apply(o *JSONError) {
+	o.Annotation = e.truncate(o.Annotation)
+	if e.omitStack {
+		o.Stack = nil
+	}
+
+	if e.fields != nil {
+		copied := *o.Error
+		o.Error = &copied
+		for k, drop := range jsonKeys {
+			if !e.fields[k] && drop != nil {
+				drop(o)
+			}
+		}
+	}
+
+	if o.Cause != nil {
+		c := *o.Cause
+		e.apply(&c)
+		o.Cause = &c
+	}
+
+	if o.Causes != nil {
+		causes := make([]JSONCause, len(o.Causes))
+		for i, c := range o.Causes {
+			c.Annotation = e.truncate(c.Annotation)
+			c.Message = e.truncate(c.Message)
+			if e.omitStack {
+				c.Stack = nil
+			}
+			causes[i] = c
+		}
+		o.Causes = causes
+	}
+}
+
+// jsonKeys maps the top-level keys of JSON documents to functions dropping
+// them. Required keys can not be dropped.
+var jsonKeys = map[string]func(o *JSONError){
+	"code":           func(o *JSONError) { o.Code = "" },
+	"desc":           func(o *JSONError) { o.Desc = "" },
+	"docs":           func(o *JSONError) { o.Docs = "" },
+	"kind":           nil,
+	"severity":       func(o *JSONError) { o.Severity = 0 },
+	"categories":     func(o *JSONError) { o.Categories = nil },
+	"schema_version": nil,
+	"ancestry":       func(o *JSONError) { o.Ancestry = nil },
+	"annotation":     func(o *JSONError) { o.Annotation = "" },
+	"fields":         func(o *JSONError) { o.Fields = nil },
+	"stack":          func(o *JSONError) { o.Stack = nil },
+	"cause":          func(o *JSONError) { o.Cause = nil },
+	"causes":         func(o *JSONError) { o.Causes = nil },
+}
+
+func (e *Encoder) truncate(s string) string {
+	if e.maxAnnotationLength == 0 || len(s) <= e.maxAnnotationLength {
+		return s
+	}
+
+	r := []rune(s)
+	if len(r) <= e.maxAnnotationLength {
+		return s
+	}
+
+	return string(r[:e.maxAnnotationLength]) + "…"
+}
@@ -0,0 +1,241 @@
+package microerror
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Test_Encoder tests encoding errors with different options.
+//
+// It uses golden file as reference and when changes to template are
+// intentional, they can be updated by providing -update flag for go test.
+//
+//	go test ./ -run Test_Encoder -update
+func Test_Encoder(t *testing.T) {
+	defer func(e []namedExtractor) { contextExtractors = e }(contextExtractors)
+	contextExtractors = nil
+
+	RegisterContextExtractor("request_id", ContextValue(testRequestIDKey{}))
+	RegisterContextExtractor("traceparent", Traceparent)
+
+	ctx := context.WithValue(context.Background(), testRequestIDKey{}, "test-request-id")
+	ctx = WithTraceparent(ctx, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	testCases := []struct {
+		name       string
+		config     EncoderConfig
+		inputErrs  func() []error
+		jsonFormat JSONFormat
+	}{
+		{
+			name:   "case 0: default config batch",
+			config: EncoderConfig{},
+			inputErrs: func() []error {
+				return []error{
+					nil,
+					Maskf(testMicroErr, "test annotation"),
+					Mask(errors.New("test error")),
+				}
+			},
+		},
+		{
+			name: "case 1: indent",
+			config: EncoderConfig{
+				Indent: "  ",
+			},
+			inputErrs: func() []error {
+				return []error{
+					Maskf(testMicroErr, "test annotation"),
+				}
+			},
+		},
+		{
+			name: "case 2: omit stack with cause",
+			config: EncoderConfig{
+				OmitStack: true,
+			},
+			inputErrs: func() []error {
+				err := Mask(errors.New("test error"))
+				err = Wrapf(testMicroErr, err, "test annotation")
+				return []error{
+					Mask(err),
+				}
+			},
+		},
+		{
+			name: "case 3: omit stack V2",
+			config: EncoderConfig{
+				OmitStack: true,
+			},
+			inputErrs: func() []error {
+				err := Mask(errors.New("test error"))
+				err = Wrapf(testMicroErr, err, "test annotation")
+				return []error{
+					Mask(err),
+				}
+			},
+			jsonFormat: JSONFormatV2,
+		},
+		{
+			name: "case 4: fields allowlist",
+			config: EncoderConfig{
+				Fields: []string{"annotation", "fields"},
+			},
+			inputErrs: func() []error {
+				return []error{
+					MaskCtx(ctx, Wrapf(testMicroErr, errors.New("test error"), "test annotation")),
+				}
+			},
+		},
+		{
+			name: "case 5: empty fields allowlist",
+			config: EncoderConfig{
+				Fields: []string{},
+			},
+			inputErrs: func() []error {
+				return []error{
+					MaskCtx(ctx, Maskf(testMicroErr, "test annotation")),
+				}
+			},
+		},
+		{
+			name: "case 6: max annotation length",
+			config: EncoderConfig{
+				MaxAnnotationLength: 4,
+				OmitStack:           true,
+			},
+			inputErrs: func() []error {
+				return []error{
+					Maskf(testMicroErr, "test annotation"),
+					Maskf(testMicroErr, "äöü"),
+					Mask(errors.New("test error")),
+				}
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			if tc.jsonFormat != 0 {
+				SetJSONFormat(tc.jsonFormat)
+				defer SetJSONFormat(JSONFormatV1)
+			}
+
+			b := &bytes.Buffer{}
+			tc.config.Writer = b
+
+			encoder, err := NewEncoder(tc.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = encoder.EncodeAll(tc.inputErrs()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertGolden(t, "encoder-"+normalizeToFileName(tc.name)+".golden", b.String())
+		})
+	}
+}
+
+// Test_Encoder_Defaults ensures encoding with the default config matches
+// JSON.
+func Test_Encoder_Defaults(t *testing.T) {
+	err := Maskf(testMicroErr, "test annotation")
+
+	b := &bytes.Buffer{}
+	encoder, encodeErr := NewEncoder(EncoderConfig{Writer: b})
+	if encodeErr != nil {
+		t.Fatal(encodeErr)
+	}
+
+	encodeErr = encoder.Encode(err)
+	if encodeErr != nil {
+		t.Fatal(encodeErr)
+	}
+
+	expected := JSON(err) + "\n"
+	if b.String() != expected {
+		t.Fatalf("got %q, want %q", b.String(), expected)
+	}
+}
+
+func Test_NewEncoder(t *testing.T) {
+	testCases := []struct {
+		name         string
+		config       EncoderConfig
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "case 0: valid config",
+			config: EncoderConfig{
+				Writer: &bytes.Buffer{},
+			},
+		},
+		{
+			name:         "case 1: missing writer",
+			config:       EncoderConfig{},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name: "case 2: negative max annotation length",
+			config: EncoderConfig{
+				Writer:              &bytes.Buffer{},
+				MaxAnnotationLength: -1,
+			},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name: "case 3: unknown key in fields allowlist",
+			config: EncoderConfig{
+				Writer: &bytes.Buffer{},
+				Fields: []string{"annotation", "request_id"},
+			},
+			errorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			_, err := NewEncoder(tc.config)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("test write error")
+}
+
+func Test_Encoder_WriteError(t *testing.T) {
+	encoder, err := NewEncoder(EncoderConfig{Writer: failingWriter{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = encoder.EncodeAll(testMicroErr, testMicroErr)
+	if err == nil || !strings.Contains(err.Error(), "test write error") {
+		t.Fatalf("error == %v, want test write error", err)
+	}
+}
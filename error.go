@@ -0,0 +1,30 @@
+package microerror
+
+import "errors"
+
+var invalidRegistrationError = &Error{
+	Kind: "invalidRegistrationError",
+}
+
+// IsInvalidRegistration asserts invalidRegistrationError.
+func IsInvalidRegistration(err error) bool {
+	return errors.Is(err, invalidRegistrationError)
+}
+
+var validationFailedError = &Error{
+	Kind: "validationFailedError",
+}
+
+// IsValidationFailed asserts validationFailedError.
+func IsValidationFailed(err error) bool {
+	return errors.Is(err, validationFailedError)
+}
+
+var invalidConfigError = &Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return errors.Is(err, invalidConfigError)
+}
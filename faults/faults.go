@@ -0,0 +1,185 @@
+// Package faults injects errors and latency into code paths to exercise
+// error handling in integration tests. Code calls Check at named sites:
+//
+//	err := faults.Check(ctx, "store.get")
+//	if err != nil {
+//		return microerror.Mask(err)
+//	}
+//
+// Tests configure an Injector with rules and scope it to a context with
+// WithInjector or to a test with Enable. Check returns nil when no Injector
+// is in scope, which is the case in production.
+package faults
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/giantswarm/microerror"
+)
+
+// Rule describes a fault injected at a site.
+type Rule struct {
+	// Site is the name passed to Check the rule applies to.
+	Site string
+
+	// Kind is the kind of the error returned by Check. No error is
+	// returned when it is nil, which is useful for rules only adding
+	// Latency.
+	Kind *microerror.Error
+	// Annotation is the annotation of the returned error.
+	Annotation string
+
+	// Nth restricts the rule to the nth call of Check at Site, counting
+	// from 1. The rule applies to every call when it is zero.
+	Nth int
+	// Probability restricts the rule to the given share of calls, e.g.
+	// 0.1 for every tenth call on average. The rule applies to every
+	// call when it is zero.
+	Probability float64
+	// Latency delays Check. When the context is done before, Check
+	// returns the error of the context.
+	Latency time.Duration
+}
+
+// Config configures an Injector created with New.
+type Config struct {
+	// Rules are evaluated in order. The first matching rule applies.
+	Rules []Rule
+	// Rand is optional and returns random numbers in [0, 1) used to
+	// evaluate Rule.Probability. It defaults to math/rand.Float64. Tests
+	// may pass a deterministic source.
+	Rand func() float64
+}
+
+// Injector holds rules and counts the calls of Check per site. It is safe
+// for concurrent use.
+type Injector struct {
+	rules []Rule
+	rand  func() float64
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func New(config Config) (*Injector, error) {
+	for i, r := range config.Rules {
+		if r.Site == "" {
+			return nil, microerror.Maskf(invalidConfigError, "%T.Rules[%d].Site must not be empty", config, i)
+		}
+		if r.Nth < 0 {
+			return nil, microerror.Maskf(invalidConfigError, "%T.Rules[%d].Nth must not be negative", config, i)
+		}
+		if r.Probability < 0 || r.Probability > 1 {
+			return nil, microerror.Maskf(invalidConfigError, "%T.Rules[%d].Probability must be between 0 and 1", config, i)
+		}
+		if r.Latency < 0 {
+			return nil, microerror.Maskf(invalidConfigError, "%T.Rules[%d].Latency must not be negative", config, i)
+		}
+	}
+
+	i := &Injector{
+		rules: config.Rules,
+		rand:  config.Rand,
+
+		calls: map[string]int{},
+	}
+
+	if i.rand == nil {
+		i.rand = rand.Float64
+	}
+
+	return i, nil
+}
+
+// Calls returns the number of calls of Check at site.
+func (i *Injector) Calls(site string) int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.calls[site]
+}
+
+// match counts the call at site and returns the rule applying to it.
+func (i *Injector) match(site string) (Rule, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.calls[site]++
+	n := i.calls[site]
+
+	for _, r := range i.rules {
+		if r.Site != site {
+			continue
+		}
+		if r.Nth != 0 && r.Nth != n {
+			continue
+		}
+		if r.Probability != 0 && i.rand() >= r.Probability {
+			continue
+		}
+
+		return r, true
+	}
+
+	return Rule{}, false
+}
+
+type injectorKey struct{}
+
+// WithInjector returns a copy of ctx in which Check uses i.
+func WithInjector(ctx context.Context, i *Injector) context.Context {
+	return context.WithValue(ctx, injectorKey{}, i)
+}
+
+var global atomic.Pointer[Injector]
+
+// Enable makes Check use i when the context passed to it has no Injector
+// until the test finishes. t is usually a *testing.T. Only its Cleanup
+// method is used, which restores the Injector enabled before. It must not
+// be used in parallel tests as it changes global state.
+func Enable(t interface{ Cleanup(func()) }, i *Injector) {
+	previous := global.Swap(i)
+	t.Cleanup(func() {
+		global.Store(previous)
+	})
+}
+
+// Check applies the first rule of the Injector in scope matching site. The
+// returned error is masked at the caller of Check, so rendered errors look
+// like the ones of real failures. Check returns nil when no Injector is in
+// scope or no rule matches.
+func Check(ctx context.Context, site string) error {
+	i, _ := ctx.Value(injectorKey{}).(*Injector)
+	if i == nil {
+		i = global.Load()
+	}
+	if i == nil {
+		return nil
+	}
+
+	r, ok := i.match(site)
+	if !ok {
+		return nil
+	}
+
+	if r.Latency > 0 {
+		timer := time.NewTimer(r.Latency)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return microerror.MaskSkip(1, ctx.Err())
+		case <-timer.C:
+		}
+	}
+
+	if r.Kind == nil {
+		return nil
+	}
+
+	return microerror.MaskfSkip(1, r.Kind, "%s", r.Annotation)
+}
@@ -0,0 +1,329 @@
+package faults
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/microerror"
+)
+
+var executionFailedError = &microerror.Error{
+	Kind: "executionFailedError",
+}
+
+var notFoundError = &microerror.Error{
+	Kind: "notFoundError",
+}
+
+func Test_Check(t *testing.T) {
+	testCases := []struct {
+		name            string
+		config          Config
+		sites           []string
+		expectedResults []string
+	}{
+		{
+			name:   "case 0: no rules",
+			config: Config{},
+			sites:  []string{"a", "a"},
+			expectedResults: []string{
+				"<nil>",
+				"<nil>",
+			},
+		},
+		{
+			name: "case 1: every call",
+			config: Config{
+				Rules: []Rule{
+					{Site: "a", Kind: executionFailedError, Annotation: "injected"},
+				},
+			},
+			sites: []string{"a", "b", "a"},
+			expectedResults: []string{
+				"execution failed error: injected",
+				"<nil>",
+				"execution failed error: injected",
+			},
+		},
+		{
+			name: "case 2: nth call",
+			config: Config{
+				Rules: []Rule{
+					{Site: "a", Kind: executionFailedError, Annotation: "third call", Nth: 3},
+				},
+			},
+			sites: []string{"a", "b", "a", "a", "a"},
+			expectedResults: []string{
+				"<nil>",
+				"<nil>",
+				"<nil>",
+				"execution failed error: third call",
+				"<nil>",
+			},
+		},
+		{
+			name: "case 3: first matching rule applies",
+			config: Config{
+				Rules: []Rule{
+					{Site: "a", Kind: notFoundError, Nth: 2},
+					{Site: "a", Kind: executionFailedError, Annotation: "%s not formatted"},
+				},
+			},
+			sites: []string{"a", "a", "a"},
+			expectedResults: []string{
+				"execution failed error: %s not formatted",
+				"not found error",
+				"execution failed error: %s not formatted",
+			},
+		},
+		{
+			name: "case 4: probability",
+			config: Config{
+				Rules: []Rule{
+					{Site: "a", Kind: executionFailedError, Probability: 0.5},
+				},
+				Rand: sequence(0.1, 0.5, 0.9, 0.4),
+			},
+			sites: []string{"a", "a", "a", "a"},
+			expectedResults: []string{
+				"execution failed error",
+				"<nil>",
+				"<nil>",
+				"execution failed error",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			injector, err := New(tc.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := WithInjector(context.Background(), injector)
+
+			var results []string
+			for _, site := range tc.sites {
+				results = append(results, errorString(Check(ctx, site)))
+			}
+
+			if !cmp.Equal(results, tc.expectedResults) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedResults, results))
+			}
+		})
+	}
+}
+
+func Test_Check_Masked(t *testing.T) {
+	injector, err := New(Config{
+		Rules: []Rule{
+			{Site: "a", Kind: executionFailedError, Annotation: "injected"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithInjector(context.Background(), injector)
+
+	_, _, line, _ := runtime.Caller(0)
+	err = Check(ctx, "a")
+
+	if !errors.Is(err, executionFailedError) {
+		t.Fatalf("expected executionFailedError got %#v", err)
+	}
+
+	stack := microerror.Stack(err)
+	if len(stack) != 1 || filepath.Base(stack[0].File) != "faults_test.go" || stack[0].Line != line+1 {
+		t.Fatalf("expected error masked at faults_test.go:%d got %#v", line+1, stack)
+	}
+
+	expected := "Execution failed: injected"
+	if microerror.Pretty(err, false) != expected {
+		t.Fatalf("expected %q got %q", expected, microerror.Pretty(err, false))
+	}
+}
+
+func Test_Check_Latency(t *testing.T) {
+	injector, err := New(Config{
+		Rules: []Rule{
+			{Site: "slow", Latency: 10 * time.Millisecond},
+			{Site: "stuck", Kind: executionFailedError, Latency: time.Hour},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithInjector(context.Background(), injector)
+
+	start := time.Now()
+	err = Check(ctx, "slow")
+	if err != nil {
+		t.Fatalf("expected nil got %#v", err)
+	}
+	if time.Since(start) < 10*time.Millisecond {
+		t.Fatalf("expected Check to be delayed")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	err = Check(ctx, "stuck")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded got %#v", err)
+	}
+	if len(microerror.Stack(err)) != 1 {
+		t.Fatalf("expected masked error")
+	}
+}
+
+func Test_Enable(t *testing.T) {
+	injector, err := New(Config{
+		Rules: []Rule{
+			{Site: "a", Kind: executionFailedError},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		Enable(t, injector)
+
+		err := Check(context.Background(), "a")
+		if !errors.Is(err, executionFailedError) {
+			t.Fatalf("expected executionFailedError got %#v", err)
+		}
+
+		// Injectors of contexts take precedence.
+		other, err := New(Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = Check(WithInjector(context.Background(), other), "a")
+		if err != nil {
+			t.Fatalf("expected nil got %#v", err)
+		}
+
+		t.Run("nested", func(t *testing.T) {
+			Enable(t, other)
+
+			err := Check(context.Background(), "a")
+			if err != nil {
+				t.Fatalf("expected nil got %#v", err)
+			}
+		})
+
+		// The outer injector is restored after the nested test.
+		err = Check(context.Background(), "a")
+		if !errors.Is(err, executionFailedError) {
+			t.Fatalf("expected executionFailedError got %#v", err)
+		}
+	})
+
+	err = Check(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("expected nil after the test finished got %#v", err)
+	}
+	if calls := injector.Calls("a"); calls != 2 {
+		t.Fatalf("expected 2 calls got %d", calls)
+	}
+}
+
+func Test_New(t *testing.T) {
+	testCases := []struct {
+		name         string
+		config       Config
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "case 0: valid config",
+			config: Config{
+				Rules: []Rule{
+					{Site: "a", Kind: executionFailedError, Nth: 1, Probability: 1, Latency: time.Second},
+				},
+			},
+		},
+		{
+			name: "case 1: empty site",
+			config: Config{
+				Rules: []Rule{
+					{Kind: executionFailedError},
+				},
+			},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name: "case 2: negative nth",
+			config: Config{
+				Rules: []Rule{
+					{Site: "a", Nth: -1},
+				},
+			},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name: "case 3: probability out of range",
+			config: Config{
+				Rules: []Rule{
+					{Site: "a", Probability: 1.5},
+				},
+			},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name: "case 4: negative latency",
+			config: Config{
+				Rules: []Rule{
+					{Site: "a", Latency: -time.Second},
+				},
+			},
+			errorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			_, err := New(tc.config)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+
+	return err.Error()
+}
+
+// sequence returns a random source returning values in order.
+func sequence(values ...float64) func() float64 {
+	var i int
+	return func() float64 {
+		v := values[i%len(values)]
+		i++
+		return v
+	}
+}
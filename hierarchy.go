@@ -0,0 +1,74 @@
+package microerror
+
+import "errors"
+
+// Categories are the categories of an Error, see IsCategory.
+type Categories []string
+
+// NewCategories returns categories to be set as Error.Categories, e.g.
+//
+//	Categories: microerror.NewCategories("client", "retryable"),
+//
+// Error holds a pointer to them as slice fields would make it incomparable.
+func NewCategories(categories ...string) *Categories {
+	c := Categories(categories)
+	return &c
+}
+
+// IsCategory reports whether the Error in the chain of err or any of its
+// ancestors declares category. This allows e.g. mapping errors to HTTP
+// status codes or making retry decisions for whole groups of kinds.
+func IsCategory(err error, category string) bool {
+	var eerr *Error
+	if !errors.As(err, &eerr) {
+		return false
+	}
+
+	var found bool
+	walkAncestors(eerr, func(e *Error) bool {
+		if e.Categories == nil {
+			return true
+		}
+		for _, c := range *e.Categories {
+			if c == category {
+				found = true
+				return false
+			}
+		}
+		return true
+	})
+
+	return found
+}
+
+// ancestry returns the kinds of all ancestors of e, parent first.
+func ancestry(e *Error) []string {
+	var kinds []string
+	walkAncestors(e, func(p *Error) bool {
+		if p != e {
+			kinds = append(kinds, p.Kind)
+		}
+		return true
+	})
+
+	return kinds
+}
+
+// walkAncestors calls f for e and its ancestors, parent after child, until f
+// returns false. Every Error is visited once, so cyclic hierarchies like
+// child -> a -> b -> a end as well.
+func walkAncestors(e *Error, f func(*Error) bool) {
+	var buf [8]*Error
+	visited := buf[:0]
+	for ; e != nil; e = e.Parent {
+		for _, v := range visited {
+			if v == e {
+				return
+			}
+		}
+		if !f(e) {
+			return
+		}
+		visited = append(visited, e)
+	}
+}
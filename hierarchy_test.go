@@ -0,0 +1,194 @@
+package microerror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	testNotFoundError = &Error{
+		Kind:       "notFoundError",
+		Categories: NewCategories("client"),
+	}
+	testClusterNotFoundError = &Error{
+		Kind:   "clusterNotFoundError",
+		Parent: testNotFoundError,
+	}
+	testNodePoolNotFoundError = &Error{
+		Kind:       "nodePoolNotFoundError",
+		Categories: NewCategories("retryable"),
+		Parent:     testClusterNotFoundError,
+	}
+)
+
+func Test_Hierarchy(t *testing.T) {
+	testCases := []struct {
+		name               string
+		inputErrorFunc     func() error
+		target             error
+		category           string
+		expectedIs         bool
+		expectedIsCategory bool
+	}{
+		{
+			name: "case 0: same kind",
+			inputErrorFunc: func() error {
+				return Maskf(testClusterNotFoundError, "test annotation")
+			},
+			target:             testClusterNotFoundError,
+			category:           "client",
+			expectedIs:         true,
+			expectedIsCategory: true,
+		},
+		{
+			name: "case 1: parent kind",
+			inputErrorFunc: func() error {
+				return Mask(Maskf(testClusterNotFoundError, "test annotation"))
+			},
+			target:             testNotFoundError,
+			category:           "retryable",
+			expectedIs:         true,
+			expectedIsCategory: false,
+		},
+		{
+			name: "case 2: grandparent kind through fmt.Errorf",
+			inputErrorFunc: func() error {
+				return fmt.Errorf("wrapped: %w", Mask(testNodePoolNotFoundError))
+			},
+			target:             testNotFoundError,
+			category:           "client",
+			expectedIs:         true,
+			expectedIsCategory: true,
+		},
+		{
+			name: "case 3: child kind does not match",
+			inputErrorFunc: func() error {
+				return Mask(testNotFoundError)
+			},
+			target:             testClusterNotFoundError,
+			category:           "retryable",
+			expectedIs:         false,
+			expectedIsCategory: false,
+		},
+		{
+			name: "case 4: unrelated kind",
+			inputErrorFunc: func() error {
+				return Mask(testClusterNotFoundError)
+			},
+			target:             testMicroErr,
+			category:           "",
+			expectedIs:         false,
+			expectedIsCategory: false,
+		},
+		{
+			name: "case 5: error=errors.New",
+			inputErrorFunc: func() error {
+				return Mask(errors.New("test error"))
+			},
+			target:             testNotFoundError,
+			category:           "client",
+			expectedIs:         false,
+			expectedIsCategory: false,
+		},
+		{
+			name: "case 6: cycle not involving the child, unrelated kind",
+			inputErrorFunc: func() error {
+				return Mask(newTestCycleError())
+			},
+			target:             testMicroErr,
+			category:           "client",
+			expectedIs:         false,
+			expectedIsCategory: false,
+		},
+		{
+			name: "case 7: cycle not involving the child, ancestor kind",
+			inputErrorFunc: func() error {
+				child := newTestCycleError()
+				return Maskf(child, "test annotation")
+			},
+			target:             testNotFoundError,
+			category:           "retryable",
+			expectedIs:         false,
+			expectedIsCategory: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := tc.inputErrorFunc()
+			if errors.Is(err, tc.target) != tc.expectedIs {
+				t.Fatalf("errors.Is = %v, want %v", !tc.expectedIs, tc.expectedIs)
+			}
+			if IsCategory(err, tc.category) != tc.expectedIsCategory {
+				t.Fatalf("IsCategory = %v, want %v", !tc.expectedIsCategory, tc.expectedIsCategory)
+			}
+		})
+	}
+}
+
+func Test_Error_Comparable(t *testing.T) {
+	copied := *testNodePoolNotFoundError
+
+	kinds := map[Error]bool{
+		*testNodePoolNotFoundError: true,
+	}
+	if !kinds[copied] {
+		t.Fatalf("expected copied Error to be equal")
+	}
+}
+
+func Test_Hierarchy_Cycle(t *testing.T) {
+	child := newTestCycleError()
+	a := child.Parent
+	b := a.Parent
+
+	if !errors.Is(Mask(child), b) {
+		t.Fatalf("errors.Is = false, want true")
+	}
+
+	kinds := ancestry(child)
+	if !cmp.Equal(kinds, []string{"aError", "bError"}) {
+		t.Fatalf("\n\n%s\n", cmp.Diff([]string{"aError", "bError"}, kinds))
+	}
+}
+
+// newTestCycleError returns a child of the cyclic hierarchy a -> b -> a.
+func newTestCycleError() *Error {
+	a := &Error{
+		Kind: "aError",
+	}
+	b := &Error{
+		Kind:       "bError",
+		Categories: NewCategories("retryable"),
+		Parent:     a,
+	}
+	a.Parent = b
+
+	return &Error{
+		Kind:   "childError",
+		Parent: a,
+	}
+}
+
+func Test_Hierarchy_JSON(t *testing.T) {
+	var o JSONError
+	err := json.Unmarshal([]byte(JSON(Mask(testNodePoolNotFoundError))), &o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"clusterNotFoundError", "notFoundError"}
+	if !cmp.Equal(o.Ancestry, expected) {
+		t.Fatalf("\n\n%s\n", cmp.Diff(expected, o.Ancestry))
+	}
+	if !cmp.Equal(o.Categories, NewCategories("retryable")) {
+		t.Fatalf("\n\n%s\n", cmp.Diff(NewCategories("retryable"), o.Categories))
+	}
+}
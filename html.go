@@ -0,0 +1,47 @@
+package microerror
+
+import (
+	"html/template"
+	"strings"
+)
+
+var htmlTemplate = template.Must(template.New("html").Funcs(template.FuncMap(TemplateFuncs())).Parse(`<div class="error">
+<h2>{{ if .Code }}{{ codePrefix .Code }}{{ end }}
+{{- if .Title }}{{ .Title | prettify | capitalize }}
+{{- else }}{{ .Annotation | prettify | capitalize }}{{ end }}</h2>
+{{- if .Desc }}
+<p>{{ .Desc }}</p>
+{{- end }}
+{{- if .Docs }}
+<p><a href="{{ .Docs }}">Documentation</a></p>
+{{- end }}
+{{- if and .Title .Annotation }}
+<p>{{ .Annotation | prettify }}{{ with .Causes }}: {{ (index . 0).Message | prettify }}{{ end }}</p>
+{{- else }}{{ with .Causes }}
+<p>{{ (index . 0).Message | prettify }}</p>
+{{- end }}{{ end }}
+{{- if .Stack }}
+<details>
+<summary>Stack</summary>
+<pre>{{ stack .Stack }}</pre>
+</details>
+{{- end }}
+</div>
+`))
+
+// HTML renders err as HTML fragment for status pages. It holds the same
+// information as Markdown with the stack in a collapsible details element.
+// All values are escaped. Nil errors are rendered as empty string.
+func HTML(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var builder strings.Builder
+	execErr := htmlTemplate.Execute(&builder, NewView(err))
+	if execErr != nil {
+		panic(execErr.Error())
+	}
+
+	return builder.String()
+}
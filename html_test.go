@@ -0,0 +1,67 @@
+package microerror
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// Test_HTML tests rendering errors as HTML.
+//
+// It uses golden file as reference and when changes to template are
+// intentional, they can be updated by providing -update flag for go test.
+//
+//	go test ./ -run Test_HTML -update
+func Test_HTML(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inputErrorFunc func() error
+	}{
+		{
+			name: "case 0: nil",
+			inputErrorFunc: func() error {
+				return nil
+			},
+		},
+		{
+			name: "case 1: error=errors.New no masking",
+			inputErrorFunc: func() error {
+				return errors.New("something went wrong")
+			},
+		},
+		{
+			name: "case 2: error=microerror.Error depth=2 Maskf",
+			inputErrorFunc: func() error {
+				err := Maskf(testMicroErr, "something bad happened")
+				err = Mask(err)
+				return err
+			},
+		},
+		{
+			name: "case 3: error=microerror.Error with code Wrapf",
+			inputErrorFunc: func() error {
+				return Wrapf(testCodeMicroErr, errors.New("test cause"), "loading config")
+			},
+		},
+		{
+			name: "case 4: error=microerror.Error with markup",
+			inputErrorFunc: func() error {
+				err := &Error{
+					Kind: "testKind",
+					Desc: "Check *all* the `settings`.",
+					Docs: "https://example.com/docs?a=<b>",
+				}
+				return Maskf(err, "field [name] is <empty>\n# not a heading")
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			actual := normalizeStackPaths(HTML(tc.inputErrorFunc()))
+			assertGolden(t, "html-"+normalizeToFileName(tc.name)+".golden", actual)
+		})
+	}
+}
@@ -2,42 +2,146 @@ package microerror
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 )
 
+// JSONFormat is the version of the document rendered by JSON.
+type JSONFormat int
+
+const (
+	// JSONFormatV1 is the flat format with a single kind, annotation and
+	// stack for the whole error chain. Layers in the middle of the chain,
+	// like fmt.Errorf wrappers, are not rendered. This is the default.
+	JSONFormatV1 JSONFormat = 1
+	// JSONFormatV2 extends JSONFormatV1 with the causes array holding every
+	// distinct layer of the chain with its own stack.
+	JSONFormatV2 JSONFormat = 2
+)
+
+var jsonFormat = JSONFormatV1
+
+// SetJSONFormat sets the format rendered by JSON. It is meant to be called
+// during program initialization.
+func SetJSONFormat(f JSONFormat) {
+	jsonFormat = f
+}
+
 // JSON prints the error with enriched information in JSON format. Enriched
 // information includes:
 //
 //   - All fields from Error type.
 //   - Error stack.
 //
-// The rendered JSON can be unmarshalled with JSONError type.
+// The rendered JSON can be unmarshalled with JSONError type and is described
+// by the JSON Schema returned by Schema.
 func JSON(err error) string {
+	bytes, err := json.Marshal(newJSONError(err))
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return string(bytes)
+}
+
+// newJSONError collects everything rendered about err walking its chain. The
+// stack is reconstructed from all stackedError, the annotation and cause are
+// taken from the outermost annotatedError and the Error is the first one
+// found. Errors not created with this package are rendered with unknown kind
+// and their message as annotation.
+func newJSONError(err error) JSONError {
 	if err == nil {
-		err = &annotatedError{
-			annotation: fmt.Sprintf("%v", nil),
-			underlying: &Error{
+		o := JSONError{
+			Error: &Error{
 				Kind: kindNil,
 			},
+			Annotation: fmt.Sprintf("%v", nil),
+
+			SchemaVersion: jsonFormat,
 		}
+		return o
 	}
 
-	var eerr *Error
+	var o JSONError
+	var severity Level
 	var serr *stackedError
-	if !errors.As(err, &eerr) && !errors.As(err, &serr) {
-		err = &annotatedError{
-			annotation: err.Error(),
-			underlying: &Error{
-				Kind: kindUnknown,
-			},
+	walk(err, func(e error) bool {
+		switch e := e.(type) {
+		case *stackedError:
+			if serr == nil {
+				serr = e
+			}
+			if severity == 0 {
+				severity = e.severity
+			}
+		case *annotatedError:
+			if o.Error == nil {
+				o.Error = e.underlying
+				o.Annotation = e.annotation
+				if e.cause != nil && jsonFormat == JSONFormatV1 {
+					c := newJSONError(e.cause)
+					// Only the document itself is versioned.
+					c.SchemaVersion = 0
+					o.Cause = &c
+				}
+			}
+		case *Error:
+			if o.Error == nil {
+				o.Error = e
+			}
+		}
+		return true
+	})
+
+	if o.Error == nil {
+		o.Error = &Error{
+			Kind: kindUnknown,
 		}
+		o.Annotation = err.Error()
 	}
 
-	bytes, err := json.Marshal(err)
-	if err != nil {
-		panic(err.Error())
+	o.Ancestry = ancestry(o.Error)
+
+	if severity != 0 {
+		copied := *o.Error
+		copied.Severity = severity
+		o.Error = &copied
 	}
 
-	return string(bytes)
+	if serr != nil {
+		o.Stack = createStackTrace(serr)
+		if collapseStacks {
+			o.Stack = collapseStack(o.Stack)
+		}
+		o.Fields = Fields(err)
+	}
+
+	o.SchemaVersion = jsonFormat
+	if jsonFormat >= JSONFormatV2 {
+		o.Causes = newJSONCauses(err)
+	}
+
+	return o
+}
+
+func newJSONCauses(err error) []JSONCause {
+	var causes []JSONCause
+	for _, l := range Layers(err) {
+		c := JSONCause{
+			Error: l.Error,
+
+			Annotation: l.Annotation,
+			Type:       l.Type,
+			Stack:      l.Stack,
+		}
+		if l.Error == nil {
+			c.Message = l.Message
+		}
+		if collapseStacks {
+			c.Stack = collapseStack(c.Stack)
+		}
+
+		causes = append(causes, c)
+	}
+
+	return causes
 }
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"flag"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"testing"
+	"time"
 	"unicode"
 
 	"github.com/google/go-cmp/cmp"
@@ -97,6 +99,52 @@ func Test_JSON(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "case 9: error=microerror.Error depth=3 Mask with time",
+			inputErrorFunc: func() error {
+				SetClock(newTestClock(1500 * time.Millisecond))
+				defer SetClock(nil)
+
+				err := Mask(testMicroErr)
+				err = Mask(err)
+				err = Mask(err)
+				return err
+			},
+		},
+		{
+			name: "case 10: error=microerror.Error depth=2 Wrapf cause=errors.New depth=2 Mask",
+			inputErrorFunc: func() error {
+				err := Mask(errors.New("test error"))
+				err = Mask(err)
+				err = Wrapf(testMicroErr, err, "test annotation")
+				err = Mask(err)
+				return err
+			},
+		},
+		{
+			name: "case 11: error=microerror.Error depth=2 Maskf wrapped with errors.Join",
+			inputErrorFunc: func() error {
+				err := Maskf(testMicroErr, "test annotation")
+				err = Mask(errors.Join(err))
+				return err
+			},
+		},
+		{
+			name: "case 12: error=microerror.Error depth=2 Maskf wrapped with fmt.Errorf multiple %w",
+			inputErrorFunc: func() error {
+				err := Maskf(testMicroErr, "test annotation")
+				err = Mask(fmt.Errorf("test wrapper %w and %w", err, errors.New("test error")))
+				return err
+			},
+		},
+		{
+			name: "case 13: error=microerror.Error depth=2 MaskWithSeverity wrapped with errors.Join",
+			inputErrorFunc: func() error {
+				err := MaskWithSeverity(testMicroErr, LevelWarning)
+				err = Mask(errors.Join(errors.New("test error"), err))
+				return err
+			},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -104,59 +152,152 @@ func Test_JSON(t *testing.T) {
 			t.Log(tc.name)
 
 			actual := JSON(tc.inputErrorFunc())
-			{
-				b := &bytes.Buffer{}
-				err := json.Indent(b, []byte(actual), "", "\t")
-				if err != nil {
-					t.Error(err)
-				}
-				actual = b.String()
-				// Add a newline for editors to stop editors
-				// complaining.
-				actual += "\n"
-			}
-			// Change paths to avoid prefixes like
-			// "/Users/username/go/src/" so this can test can be
-			// executed on different machines.
-			//
-			// E.g: This:
-			//
-			//	"file":"/Users/username/go/src/github.com/giantswarm/microerror/json_test.go"
-			//
-			// Should be replaced with:
-			//
-			//	"file":"--REPLACED--/github.com/giantswarm/microerror/json_test.go"
-			//
-			{
-				r := regexp.MustCompile(`("file"\s*:\s*")\S+(/[^/"]+.go")`)
-				actual = r.ReplaceAllString(actual, "$1--REPLACED--$2")
-			}
+			assertJSONGolden(t, normalizeToFileName(tc.name)+".golden", actual)
+		})
+	}
+}
 
-			var expected string
-			{
-				golden := filepath.Join("testdata", normalizeToFileName(tc.name)+".golden")
-				if *update {
-					err := os.WriteFile(golden, []byte(actual), 0644) //nolint:gosec
-					if err != nil {
-						t.Fatal(err)
-					}
-				}
-
-				bytes, err := os.ReadFile(golden) // nolint:gosec
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				expected = string(bytes)
-			}
+// Test_JSON_V2 tests marshaling errors to JSON using JSONFormatV2.
+//
+//	go test ./ -run Test_JSON_V2 -update
+func Test_JSON_V2(t *testing.T) {
+	SetJSONFormat(JSONFormatV2)
+	defer SetJSONFormat(JSONFormatV1)
 
-			if actual != expected {
-				t.Fatalf("\n\n%s\n", cmp.Diff(actual, expected))
-			}
+	testCases := []struct {
+		name           string
+		inputErrorFunc func() error
+	}{
+		{
+			name: "case 0: error=microerror.Error no masking",
+			inputErrorFunc: func() error {
+				return testMicroErr
+			},
+		},
+		{
+			name: "case 1: error=microerror.Error depth=2 Maskf",
+			inputErrorFunc: func() error {
+				err := Maskf(testMicroErr, "test annotation")
+				err = Mask(err)
+				return err
+			},
+		},
+		{
+			name: "case 2: error=microerror.Error wrapped with fmt.Errorf",
+			inputErrorFunc: func() error {
+				err := Maskf(testMicroErr, "test annotation")
+				err = Mask(fmt.Errorf("test wrapper: %w", err))
+				return err
+			},
+		},
+		{
+			name: "case 3: error=microerror.Error depth=2 Wrapf cause=errors.New depth=1 Mask",
+			inputErrorFunc: func() error {
+				err := Mask(errors.New("test error"))
+				err = Wrapf(testMicroErr, err, "test annotation")
+				err = Mask(err)
+				return err
+			},
+		},
+		{
+			name: "case 4: nil",
+			inputErrorFunc: func() error {
+				return nil
+			},
+		},
+		{
+			name: "case 5: error=microerror.Error depth=2 Maskf wrapped with errors.Join",
+			inputErrorFunc: func() error {
+				err := Maskf(testMicroErr, "test annotation")
+				err = Mask(errors.Join(err, Mask(errors.New("test error"))))
+				return err
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			actual := JSON(tc.inputErrorFunc())
+			assertJSONGolden(t, "v2-"+normalizeToFileName(tc.name)+".golden", actual)
 		})
 	}
 }
 
+// assertJSONGolden compares indented actual with the golden file in
+// testdata. The golden file is updated when the -update flag is given.
+func assertJSONGolden(t *testing.T, golden string, actual string) {
+	t.Helper()
+
+	{
+		b := &bytes.Buffer{}
+		err := json.Indent(b, []byte(actual), "", "\t")
+		if err != nil {
+			t.Error(err)
+		}
+		actual = b.String()
+		// Add a newline for editors to stop editors
+		// complaining.
+		actual += "\n"
+	}
+
+	assertGolden(t, golden, actual)
+}
+
+// assertGolden compares actual with the golden file in testdata after
+// replacing file paths. The golden file is updated when the -update flag is
+// given.
+func assertGolden(t *testing.T, golden string, actual string) {
+	t.Helper()
+
+	// Change paths to avoid prefixes like
+	// "/Users/username/go/src/" so this can test can be
+	// executed on different machines.
+	//
+	// E.g: This:
+	//
+	//	"file":"/Users/username/go/src/github.com/giantswarm/microerror/json_test.go"
+	//
+	// Should be replaced with:
+	//
+	//	"file":"--REPLACED--/github.com/giantswarm/microerror/json_test.go"
+	//
+	{
+		r := regexp.MustCompile(`("file"\s*:\s*")\S+(/[^/"]+.go")`)
+		actual = r.ReplaceAllString(actual, "$1--REPLACED--$2")
+	}
+
+	var expected string
+	{
+		golden := filepath.Join("testdata", golden)
+		if *update {
+			err := os.WriteFile(golden, []byte(actual), 0644) //nolint:gosec
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		bytes, err := os.ReadFile(golden) // nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected = string(bytes)
+	}
+
+	if actual != expected {
+		t.Fatalf("\n\n%s\n", cmp.Diff(actual, expected))
+	}
+}
+
+// normalizeStackPaths changes paths of stack entries rendered as
+// "file:line" the same way as assertGolden does for JSON.
+func normalizeStackPaths(s string) string {
+	r := regexp.MustCompile(`[^\s",=>(]+(/[^/\s",]+\.go:)`)
+	return r.ReplaceAllString(s, "--REPLACED--$1")
+}
+
 // normalizeToFileName converts all non-digit, non-letter runes in input string
 // to dash ('-'). Coalesces multiple dashes into one.
 func normalizeToFileName(s string) string {
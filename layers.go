@@ -0,0 +1,86 @@
+package microerror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Layer is a single distinct error of an error chain together with the
+// stack entries recorded while masking it.
+type Layer struct {
+	// Error is the Error of the layer. It is nil for errors not created
+	// with this package.
+	Error      *Error
+	Annotation string
+	// Message is what Error() of the layer returns.
+	Message string
+	// Type is the Go type of the layer, e.g. "*fmt.wrapError".
+	Type  string
+	Stack []StackEntry
+}
+
+// Layers splits the chain of err into its distinct layers, outermost first.
+// Masking does not create layers of its own. Instead the stack entries are
+// attached to the layer they were recorded on. E.g. this chain has two
+// layers, one of type *fmt.wrapError with a single stack entry and one of
+// kind notFoundError with two stack entries:
+//
+//	err := microerror.Maskf(notFoundError, "cluster %#q", id)
+//	err = microerror.Mask(err)
+//	err = microerror.Mask(fmt.Errorf("reconciling: %w", err))
+//
+// Layers of errors wrapping several errors, like the ones created by
+// errors.Join, are followed by the layers of each wrapped error in order.
+func Layers(err error) []Layer {
+	return appendLayers(nil, err)
+}
+
+func appendLayers(layers []Layer, err error) []Layer {
+	for err != nil {
+		var stack []StackEntry
+		for {
+			serr, ok := err.(*stackedError)
+			if !ok {
+				break
+			}
+			stack = append(stack, serr.entry())
+			err = serr.underlying
+		}
+		for i, j := 0, len(stack)-1; i < j; i, j = i+1, j-1 {
+			stack[i], stack[j] = stack[j], stack[i]
+		}
+		if w, ok := err.(*wrappedError); ok {
+			err = w.annotatedError
+		}
+
+		l := Layer{
+			Message: err.Error(),
+			Type:    fmt.Sprintf("%T", err),
+			Stack:   stack,
+		}
+
+		var next error
+		switch e := err.(type) {
+		case *annotatedError:
+			l.Error = e.underlying
+			l.Annotation = e.annotation
+			l.Type = fmt.Sprintf("%T", e.underlying)
+			next = e.cause
+		case *Error:
+			l.Error = e
+		case interface{ Unwrap() []error }:
+			layers = append(layers, l)
+			for _, u := range e.Unwrap() {
+				layers = appendLayers(layers, u)
+			}
+			return layers
+		default:
+			next = errors.Unwrap(err)
+		}
+
+		layers = append(layers, l)
+		err = next
+	}
+
+	return layers
+}
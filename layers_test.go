@@ -0,0 +1,90 @@
+package microerror
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func Test_Layers(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inputErrorFunc func() error
+		expectedLayers []Layer
+	}{
+		{
+			name: "case 0: nil",
+			inputErrorFunc: func() error {
+				return nil
+			},
+			expectedLayers: nil,
+		},
+		{
+			name: "case 1: error=microerror.Error no masking",
+			inputErrorFunc: func() error {
+				return testMicroErr
+			},
+			expectedLayers: []Layer{
+				{
+					Error:   testMicroErr,
+					Message: "test kind",
+					Type:    "*microerror.Error",
+				},
+			},
+		},
+		{
+			name: "case 2: error=microerror.Error depth=2 Maskf",
+			inputErrorFunc: func() error {
+				err := Maskf(testMicroErr, "test annotation")
+				return Mask(err)
+			},
+			expectedLayers: []Layer{
+				{
+					Error:      testMicroErr,
+					Annotation: "test annotation",
+					Message:    "test kind: test annotation",
+					Type:       "*microerror.Error",
+					Stack:      []StackEntry{{Line: 42}, {Line: 43}},
+				},
+			},
+		},
+		{
+			name: "case 3: error=errors.New wrapped with fmt.Errorf",
+			inputErrorFunc: func() error {
+				err := Mask(errors.New("test error"))
+				err = Mask(err)
+				return Mask(fmt.Errorf("test wrapper: %w", err))
+			},
+			expectedLayers: []Layer{
+				{
+					Message: "test wrapper: test error",
+					Type:    "*fmt.wrapError",
+					Stack:   []StackEntry{{Line: 60}},
+				},
+				{
+					Message: "test error",
+					Type:    "*errors.errorString",
+					Stack:   []StackEntry{{Line: 58}, {Line: 59}},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			layers := Layers(tc.inputErrorFunc())
+
+			// Only compare line numbers of stack entries.
+			opt := cmpopts.IgnoreFields(StackEntry{}, "File", "PC")
+			if !cmp.Equal(layers, tc.expectedLayers, opt) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedLayers, layers, opt))
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package microerror
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Logfmt prints the error with the information rendered by JSON as logfmt
+// key=value pairs, e.g.
+//
+//	kind=testKind desc=test-desc annotation="test annotation" stack="a.go:12,b.go:34"
+//
+// Empty values are omitted. The cause set with Wrapf is rendered with
+// cause_kind and cause_annotation keys in every JSONFormat set with
+// SetJSONFormat. Fields, see Fields, are rendered with their own keys after
+// the keys of the error. Fields named like one of these keys are prefixed
+// with "field_", e.g. field_kind. Stack entries
+// collapsed by SetStackCollapsing are rendered with their count like
+// "a.go:12*4", and repeated cycles in parentheses like "(a.go:12,b.go:34)*4".
+func Logfmt(err error) string {
+	return string(AppendLogfmt(nil, err))
+}
+
+// AppendLogfmt appends the output of Logfmt to dst and returns the extended
+// buffer.
+func AppendLogfmt(dst []byte, err error) []byte {
+	o := newJSONError(err)
+
+	dst = appendLogfmtPair(dst, "kind", o.Kind)
+	dst = appendLogfmtPair(dst, "code", o.Code)
+	if o.Severity != 0 {
+		dst = appendLogfmtPair(dst, "severity", o.Severity.String())
+	}
+	dst = appendLogfmtPair(dst, "desc", o.Desc)
+	dst = appendLogfmtPair(dst, "docs", o.Docs)
+	dst = appendLogfmtPair(dst, "annotation", o.Annotation)
+
+	// The cause is only part of o in JSONFormatV1, see SetJSONFormat.
+	var aerr *annotatedError
+	if errors.As(err, &aerr) && aerr.cause != nil {
+		c := newJSONError(aerr.cause)
+		dst = appendLogfmtPair(dst, "cause_kind", c.Kind)
+		dst = appendLogfmtPair(dst, "cause_annotation", c.Annotation)
+	}
+
+	keys := make([]string, 0, len(o.Fields))
+	for k := range o.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		key := k
+		if logfmtKeys[k] {
+			key = "field_" + k
+		}
+		dst = appendLogfmtPair(dst, key, o.Fields[k])
+	}
+
+	dst = appendLogfmtPair(dst, "stack", logfmtStack(o.Stack))
+
+	return dst
+}
+
+// logfmtKeys are the keys of the error rendered by AppendLogfmt.
+var logfmtKeys = map[string]bool{
+	"kind":             true,
+	"code":             true,
+	"severity":         true,
+	"desc":             true,
+	"docs":             true,
+	"annotation":       true,
+	"cause_kind":       true,
+	"cause_annotation": true,
+	"stack":            true,
+}
+
+// logfmtStack renders stack as comma separated file:line entries.
+// Collapsed entries and cycles are followed by their count.
+func logfmtStack(stack []StackEntry) string {
+	// cycles marks the first entry of every collapsed cycle.
+	cycles := map[int]bool{}
+	for i, e := range stack {
+		if e.Count > 1 && e.Cycle > 1 {
+			cycles[i-e.Cycle+1] = true
+		}
+	}
+
+	var b strings.Builder
+	for i, e := range stack {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if cycles[i] {
+			b.WriteByte('(')
+		}
+		b.WriteString(e.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(e.Line))
+		if e.Count > 1 {
+			if e.Cycle > 1 {
+				b.WriteByte(')')
+			}
+			b.WriteByte('*')
+			b.WriteString(strconv.Itoa(e.Count))
+		}
+	}
+
+	return b.String()
+}
+
+func appendLogfmtPair(dst []byte, key string, value string) []byte {
+	if value == "" {
+		return dst
+	}
+	if len(dst) > 0 {
+		dst = append(dst, ' ')
+	}
+
+	// Keys can't be quoted so characters not allowed in them are
+	// replaced.
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			r = '_'
+		}
+		dst = utf8.AppendRune(dst, r)
+	}
+	dst = append(dst, '=')
+
+	if needsLogfmtQuoting(value) {
+		return strconv.AppendQuote(dst, value)
+	}
+
+	return append(dst, value...)
+}
+
+func needsLogfmtQuoting(value string) bool {
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,135 @@
+package microerror
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+type testLogfmtKindKey struct{}
+
+// Test_Logfmt tests rendering errors as logfmt.
+//
+// It uses golden file as reference and when changes to template are
+// intentional, they can be updated by providing -update flag for go test.
+//
+//	go test ./ -run Test_Logfmt -update
+func Test_Logfmt(t *testing.T) {
+	defer func(e []namedExtractor) { contextExtractors = e }(contextExtractors)
+	contextExtractors = nil
+
+	RegisterContextExtractor("request id", ContextValue(testRequestIDKey{}))
+	RegisterContextExtractor("kind", ContextValue(testLogfmtKindKey{}))
+
+	testCases := []struct {
+		name           string
+		inputErrorFunc func() error
+		collapseStack  bool
+		jsonFormat     JSONFormat
+	}{
+		{
+			name: "case 0: nil",
+			inputErrorFunc: func() error {
+				return nil
+			},
+		},
+		{
+			name: "case 1: error=errors.New no masking",
+			inputErrorFunc: func() error {
+				return errors.New("test error")
+			},
+		},
+		{
+			name: "case 2: error=microerror.Error depth=3 Maskf",
+			inputErrorFunc: func() error {
+				err := Maskf(testMicroErr, "test annotation")
+				err = Mask(err)
+				err = Mask(err)
+				return err
+			},
+		},
+		{
+			name: "case 3: error=microerror.Error with code and severity",
+			inputErrorFunc: func() error {
+				return MaskWithSeverity(Maskf(testCodeMicroErr, "test annotation"), LevelCritical)
+			},
+		},
+		{
+			name: "case 4: error=microerror.Error Wrapf cause=errors.New",
+			inputErrorFunc: func() error {
+				return Wrapf(testMicroErr, errors.New("test error"), "test annotation")
+			},
+		},
+		{
+			name: "case 5: annotation requiring escaping",
+			inputErrorFunc: func() error {
+				return Maskf(testMicroErr, "key=\"value\"\n\tC:\\path ✓")
+			},
+		},
+		{
+			name: "case 6: fields",
+			inputErrorFunc: func() error {
+				ctx := context.WithValue(context.Background(), testRequestIDKey{}, "test request id")
+				return MaskCtx(ctx, testMicroErr)
+			},
+		},
+		{
+			name: "case 7: fields named like keys of the error",
+			inputErrorFunc: func() error {
+				ctx := context.WithValue(context.Background(), testLogfmtKindKey{}, "test kind")
+				return MaskCtx(ctx, testMicroErr)
+			},
+		},
+		{
+			name: "case 8: collapsed stack",
+			inputErrorFunc: func() error {
+				err := Mask(testMicroErr)
+				for i := 0; i < 3; i++ {
+					err = Mask(err)
+				}
+				for i := 0; i < 2; i++ {
+					err = Mask(err)
+					err = Mask(err)
+				}
+				return err
+			},
+			collapseStack: true,
+		},
+		{
+			name: "case 9: error=microerror.Error Wrapf cause=errors.New JSONFormatV2",
+			inputErrorFunc: func() error {
+				return Wrapf(testMicroErr, errors.New("test error"), "test annotation")
+			},
+			jsonFormat: JSONFormatV2,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			SetStackCollapsing(tc.collapseStack)
+			defer SetStackCollapsing(false)
+
+			if tc.jsonFormat != 0 {
+				SetJSONFormat(tc.jsonFormat)
+				defer SetJSONFormat(JSONFormatV1)
+			}
+
+			actual := normalizeStackPaths(Logfmt(tc.inputErrorFunc()) + "\n")
+
+			assertGolden(t, "logfmt-"+normalizeToFileName(tc.name)+".golden", actual)
+		})
+	}
+}
+
+func Test_AppendLogfmt(t *testing.T) {
+	dst := []byte("level=error")
+	dst = AppendLogfmt(dst, testMicroErr)
+
+	expected := "level=error kind=testKind desc=test-desc docs=test-docs"
+	if string(dst) != expected {
+		t.Fatalf("got %q, want %q", dst, expected)
+	}
+}
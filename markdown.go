@@ -0,0 +1,84 @@
+package microerror
+
+import (
+	"strings"
+	"text/template"
+)
+
+var markdownTemplate = template.Must(template.New("markdown").Funcs(TemplateFuncs()).Funcs(template.FuncMap{
+	"escape": escapeMarkdown,
+	"link":   escapeMarkdownLink,
+}).Parse(`## {{ if .Code }}{{ codePrefix .Code | escape }}{{ end }}
+{{- if .Title }}{{ .Title | prettify | capitalize | escape }}
+{{- else }}{{ .Annotation | prettify | capitalize | escape }}{{ end }}
+{{- if .Desc }}
+
+{{ escape .Desc }}
+{{- end }}
+{{- if .Docs }}
+
+[Documentation](<{{ link .Docs }}>)
+{{- end }}
+{{- if and .Title .Annotation }}
+
+{{ .Annotation | prettify | escape }}{{ with .Causes }}: {{ (index . 0).Message | prettify | escape }}{{ end }}
+{{- else }}{{ with .Causes }}
+
+{{ (index . 0).Message | prettify | escape }}
+{{- end }}{{ end }}
+{{- if .Stack }}
+
+` + "```" + `text
+{{ stack .Stack }}
+` + "```" + `
+{{- end }}
+`))
+
+// Markdown renders err as Markdown for issues and status pages. It consists
+// of a heading with the kind as rendered by Pretty, the description, a link
+// to the documentation, the annotation and the stack as code block. Nil
+// errors are rendered as empty string.
+func Markdown(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var builder strings.Builder
+	execErr := markdownTemplate.Execute(&builder, NewView(err))
+	if execErr != nil {
+		panic(execErr.Error())
+	}
+
+	return builder.String()
+}
+
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`#`, `\#`,
+	`|`, `\|`,
+	`!`, `\!`,
+)
+
+// escapeMarkdown escapes characters with inline meaning in Markdown. Line
+// breaks are kept as hard line breaks.
+func escapeMarkdown(s string) string {
+	s = markdownReplacer.Replace(s)
+	return strings.ReplaceAll(s, "\n", "\\\n")
+}
+
+var markdownLinkReplacer = strings.NewReplacer(
+	`<`, `%3C`,
+	`>`, `%3E`,
+	"\n", `%0A`,
+)
+
+func escapeMarkdownLink(s string) string {
+	return markdownLinkReplacer.Replace(s)
+}
@@ -0,0 +1,151 @@
+// Package metrics counts occurrences of errors by kind and by the site they
+// were masked at. It does not depend on any metrics library. Counters are
+// available with Recorder.Snapshot, optionally published via expvar and
+// forwarded to an Adapter, e.g. one incrementing a Prometheus counter
+// vector:
+//
+//	type prometheusAdapter struct {
+//		counter *prometheus.CounterVec
+//	}
+//
+//	func (a prometheusAdapter) Inc(kind, site string) {
+//		a.counter.WithLabelValues(kind, site).Inc()
+//	}
+package metrics
+
+import (
+	"errors"
+	"expvar"
+	"strconv"
+	"sync"
+
+	"github.com/giantswarm/microerror"
+)
+
+// KindUnknown is the kind errors not created with microerror are counted
+// as.
+const KindUnknown = "unknown"
+
+// Adapter receives every error counted by a Recorder.
+type Adapter interface {
+	// Inc is called once for every recorded error with its kind and the
+	// site it was first masked at. The site is empty for errors never
+	// masked.
+	Inc(kind, site string)
+}
+
+// Config configures a Recorder created with New.
+type Config struct {
+	// Adapter is optional and receives every recorded error.
+	Adapter Adapter
+	// ExpvarName is optional. When set the Snapshot of the Recorder is
+	// published via expvar under this name. It must not be published
+	// already.
+	ExpvarName string
+}
+
+// Snapshot holds the counters of a Recorder at a point in time.
+type Snapshot struct {
+	// Total is the number of recorded errors.
+	Total uint64 `json:"total"`
+	// Kinds maps error kinds to the number of recorded errors.
+	Kinds map[string]uint64 `json:"kinds"`
+	// Sites maps "file:line" of the site errors were first masked at to
+	// the number of recorded errors. Errors never masked are not counted.
+	Sites map[string]uint64 `json:"sites"`
+}
+
+// Recorder counts errors. It is safe for concurrent use.
+type Recorder struct {
+	adapter Adapter
+
+	mu    sync.Mutex
+	total uint64
+	kinds map[string]uint64
+	sites map[string]uint64
+}
+
+func New(config Config) (*Recorder, error) {
+	if config.ExpvarName != "" && expvar.Get(config.ExpvarName) != nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.ExpvarName %#q must not be published already", config, config.ExpvarName)
+	}
+
+	r := &Recorder{
+		adapter: config.Adapter,
+
+		kinds: map[string]uint64{},
+		sites: map[string]uint64{},
+	}
+
+	if config.ExpvarName != "" {
+		expvar.Publish(config.ExpvarName, expvar.Func(func() interface{} {
+			return r.Snapshot()
+		}))
+	}
+
+	return r, nil
+}
+
+// Record counts err. It does nothing when err is nil.
+func (r *Recorder) Record(err error) {
+	if err == nil {
+		return
+	}
+
+	kind := KindUnknown
+	var eErr *microerror.Error
+	if errors.As(err, &eErr) {
+		kind = eErr.Kind
+	}
+
+	var site string
+	stack := microerror.Stack(err)
+	if len(stack) > 0 {
+		site = stack[0].File + ":" + strconv.Itoa(stack[0].Line)
+	}
+
+	r.mu.Lock()
+	r.total++
+	r.kinds[kind]++
+	if site != "" {
+		r.sites[site]++
+	}
+	r.mu.Unlock()
+
+	if r.adapter != nil {
+		r.adapter.Inc(kind, site)
+	}
+}
+
+// Mask is like microerror.Mask but also records err. The masking site is
+// the caller of Mask.
+func (r *Recorder) Mask(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	err = microerror.MaskSkip(1, err)
+	r.Record(err)
+
+	return err
+}
+
+// Snapshot returns a copy of the current counters.
+func (r *Recorder) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := Snapshot{
+		Total: r.total,
+		Kinds: make(map[string]uint64, len(r.kinds)),
+		Sites: make(map[string]uint64, len(r.sites)),
+	}
+	for k, v := range r.kinds {
+		s.Kinds[k] = v
+	}
+	for k, v := range r.sites {
+		s.Sites[k] = v
+	}
+
+	return s
+}
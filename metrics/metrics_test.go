@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/microerror"
+)
+
+var testMicroErr = &microerror.Error{
+	Kind: "testKind",
+}
+
+type testAdapter struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (a *testAdapter) Inc(kind, site string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.calls = append(a.calls, kind+" "+filepath.Base(site))
+}
+
+func Test_Recorder(t *testing.T) {
+	_, file, line, _ := runtime.Caller(0)
+	site := func(offset int) string {
+		return file + ":" + strconv.Itoa(line+offset)
+	}
+	maskedErr := microerror.Maskf(testMicroErr, "test annotation")
+	maskedTwiceErr := microerror.Mask(maskedErr)
+
+	testCases := []struct {
+		name             string
+		recordFunc       func(r *Recorder)
+		expectedSnapshot Snapshot
+		expectedCalls    []string
+	}{
+		{
+			name: "case 0: nothing recorded",
+			recordFunc: func(r *Recorder) {
+				r.Record(nil)
+				_ = r.Mask(nil)
+			},
+			expectedSnapshot: Snapshot{
+				Kinds: map[string]uint64{},
+				Sites: map[string]uint64{},
+			},
+		},
+		{
+			name: "case 1: Record counts by kind and origin",
+			recordFunc: func(r *Recorder) {
+				r.Record(maskedErr)
+				r.Record(maskedTwiceErr)
+				r.Record(testMicroErr)
+				r.Record(errors.New("test error"))
+			},
+			expectedSnapshot: Snapshot{
+				Total: 4,
+				Kinds: map[string]uint64{
+					"testKind": 3,
+					"unknown":  1,
+				},
+				Sites: map[string]uint64{
+					site(4): 2,
+				},
+			},
+			expectedCalls: []string{
+				"testKind metrics_test.go:" + strconv.Itoa(line+4),
+				"testKind metrics_test.go:" + strconv.Itoa(line+4),
+				"testKind .",
+				"unknown .",
+			},
+		},
+		{
+			name: "case 2: Mask records the caller",
+			recordFunc: func(r *Recorder) {
+				for i := 0; i < 2; i++ {
+					_ = r.Mask(errors.New("test error"))
+				}
+			},
+			expectedSnapshot: Snapshot{
+				Total: 2,
+				Kinds: map[string]uint64{
+					"unknown": 2,
+				},
+				Sites: map[string]uint64{
+					site(53): 2,
+				},
+			},
+			expectedCalls: []string{
+				"unknown metrics_test.go:" + strconv.Itoa(line+53),
+				"unknown metrics_test.go:" + strconv.Itoa(line+53),
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			adapter := &testAdapter{}
+			r, err := New(Config{Adapter: adapter})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.recordFunc(r)
+
+			snapshot := r.Snapshot()
+			if !cmp.Equal(snapshot, tc.expectedSnapshot) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedSnapshot, snapshot))
+			}
+			if !cmp.Equal(adapter.calls, tc.expectedCalls) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedCalls, adapter.calls))
+			}
+		})
+	}
+}
+
+func Test_Recorder_Expvar(t *testing.T) {
+	r, err := New(Config{ExpvarName: "microerror_test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Record(testMicroErr)
+
+	var snapshot Snapshot
+	err = json.Unmarshal([]byte(expvar.Get("microerror_test").String()), &snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Total != 1 || snapshot.Kinds["testKind"] != 1 {
+		t.Fatalf("unexpected snapshot %#v", snapshot)
+	}
+
+	_, err = New(Config{ExpvarName: "microerror_test"})
+	if !IsInvalidConfig(err) {
+		t.Fatalf("expected invalid config error got %#v", err)
+	}
+}
+
+func Test_Recorder_Concurrent(t *testing.T) {
+	r, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = r.Mask(testMicroErr)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if total := r.Snapshot().Total; total != 1000 {
+		t.Fatalf("expected 1000 errors got %d", total)
+	}
+}
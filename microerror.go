@@ -34,10 +34,53 @@ func Maskf(err *Error, f string, v ...interface{}) error {
 		annotation: fmt.Sprintf(f, v...),
 		underlying: err,
 	}
+	if localizeAnnotations {
+		aerr.format = f
+		aerr.args = renderArgs(f, v)
+	}
 
 	return mask(aerr)
 }
 
+// MaskfSkip is like Maskf but records the caller skip frames above the
+// caller of MaskfSkip. It is meant for helpers creating errors on behalf of
+// their callers. MaskfSkip(0, err, f, v...) is equivalent to
+// Maskf(err, f, v...).
+func MaskfSkip(skip int, err *Error, f string, v ...interface{}) error {
+	aerr := &annotatedError{
+		annotation: fmt.Sprintf(f, v...),
+		underlying: err,
+	}
+	if localizeAnnotations {
+		aerr.format = f
+		aerr.args = renderArgs(f, v)
+	}
+
+	return maskSkip(skip, aerr)
+}
+
+// Wrapf is like Maskf but also retains cause as the error kind was caused
+// by, e.g. an invalidConfigError caused by os.ErrNotExist. errors.Is and
+// errors.As match both kind and cause. When cause is nil Wrapf behaves like
+// Maskf.
+func Wrapf(kind *Error, cause error, f string, v ...interface{}) error {
+	aerr := &annotatedError{
+		annotation: fmt.Sprintf(f, v...),
+		underlying: kind,
+
+		cause: cause,
+	}
+	if localizeAnnotations {
+		aerr.format = f
+		aerr.args = renderArgs(f, v)
+	}
+	if cause == nil {
+		return mask(aerr)
+	}
+
+	return mask(&wrappedError{aerr})
+}
+
 func Mask(err error) error {
 	if err == nil {
 		return nil
@@ -46,15 +89,78 @@ func Mask(err error) error {
 	return mask(err)
 }
 
-func mask(err error) error {
-	pc, file, line, _ := runtime.Caller(2)
+// MaskSkip is like Mask but records the caller skip frames above the
+// caller of MaskSkip. It is meant for helpers masking errors on behalf of
+// their callers. MaskSkip(0, err) is equivalent to Mask(err).
+func MaskSkip(skip int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return maskSkip(skip, err)
+}
 
-	return &stackedError{
-		stackEntry: StackEntry{
-			File: file,
-			Line: line,
-			PC:   pc,
-		},
+// mask records the caller of the exported function calling mask. Only the
+// program counter is recorded. Resolving it to a file and line is deferred
+// until the stack is rendered, see stackedError.entry.
+func mask(err error) *stackedError {
+	return maskSkip(1, err)
+}
+
+// maskSkip is like mask but skips skip more frames between maskSkip and the
+// exported function calling it.
+func maskSkip(skip int, err error) *stackedError {
+	// Skip runtime.Callers, maskSkip and the exported function calling
+	// maskSkip.
+	var pcs [1]uintptr
+	runtime.Callers(3+skip, pcs[:])
+
+	serr := &stackedError{
+		pc:         pcs[0],
 		underlying: err,
 	}
+	if clock != nil {
+		serr.time = clock()
+	}
+	if recordGoroutines {
+		serr.goroutine = goroutineID()
+	}
+	if f := frameFunc.Load(); f != nil {
+		file, line := (*f)(serr.pc)
+		serr.frame = &StackEntry{File: file, Line: line, PC: serr.pc}
+	}
+
+	return serr
+}
+
+// walk calls f for err and the errors wrapped by it in the order errors.As
+// visits them until f returns false. Errors wrapping several errors, like
+// the ones created by errors.Join, are walked depth first. The cause of an
+// error created with Wrapf is not visited as it is rendered on its own.
+func walk(err error, f func(error) bool) bool {
+	for err != nil {
+		if !f(err) {
+			return false
+		}
+
+		switch e := err.(type) {
+		case *annotatedError:
+			return true
+		case *wrappedError:
+			err = e.annotatedError
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, u := range e.Unwrap() {
+				if !walk(u, f) {
+					return false
+				}
+			}
+			return true
+		default:
+			return true
+		}
+	}
+
+	return true
 }
@@ -3,6 +3,7 @@ package microerror
 import (
 	"errors"
 	"fmt"
+	"runtime"
 	"strconv"
 	"testing"
 )
@@ -225,3 +226,144 @@ func Test_Mask_Error(t *testing.T) {
 		})
 	}
 }
+
+func Test_Wrapf(t *testing.T) {
+	testWrapfCauseError := errors.New("test cause error")
+
+	testCases := []struct {
+		name           string
+		inputErrorFunc func() error
+		expectedError  string
+		expectedPretty string
+		expectedCause  error
+	}{
+		{
+			name: "case 0: cause=errors.New",
+			inputErrorFunc: func() error {
+				return Wrapf(testMicroErr, testWrapfCauseError, "test annotation")
+			},
+			expectedError:  "test kind: test annotation: test cause error",
+			expectedPretty: "Test kind: test annotation: test cause",
+			expectedCause:  testWrapfCauseError,
+		},
+		{
+			name: "case 1: cause=errors.New depth=3 empty annotation",
+			inputErrorFunc: func() error {
+				err := Wrapf(testMicroErr, Mask(testWrapfCauseError), "")
+				err = Mask(err)
+				return Mask(err)
+			},
+			expectedError:  "test kind: test cause error",
+			expectedPretty: "Test kind: test cause",
+			expectedCause:  testWrapfCauseError,
+		},
+		{
+			name: "case 2: cause=microerror.Error",
+			inputErrorFunc: func() error {
+				return Wrapf(testMicroErr, Maskf(testCodeMicroErr, "inner annotation"), "test annotation")
+			},
+			expectedError:  "test kind: test annotation: invalid config error: inner annotation",
+			expectedPretty: "Test kind: test annotation: invalid config error: inner annotation",
+			expectedCause:  testCodeMicroErr,
+		},
+		{
+			name: "case 3: cause=nil",
+			inputErrorFunc: func() error {
+				return Wrapf(testMicroErr, nil, "test annotation")
+			},
+			expectedError:  "test kind: test annotation",
+			expectedPretty: "Test kind: test annotation",
+			expectedCause:  nil,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := tc.inputErrorFunc()
+			if err.Error() != tc.expectedError {
+				t.Errorf("err.Error() = %#q, want %#q", err.Error(), tc.expectedError)
+			}
+			if Pretty(err, false) != tc.expectedPretty {
+				t.Errorf("Pretty(err) = %#q, want %#q", Pretty(err, false), tc.expectedPretty)
+			}
+			if !errors.Is(err, testMicroErr) {
+				t.Errorf("expected err to match kind")
+			}
+			if tc.expectedCause != nil && !errors.Is(err, tc.expectedCause) {
+				t.Errorf("expected err to match cause")
+			}
+			if Cause(err) != testMicroErr {
+				t.Errorf("Cause(err) = %#v, want %#v", Cause(err), testMicroErr)
+			}
+		})
+	}
+}
+
+func Test_Maskf_Unwrap(t *testing.T) {
+	err := Maskf(testMicroErr, "test annotation")
+
+	unwrapped := errors.Unwrap(errors.Unwrap(err))
+	if unwrapped != testMicroErr {
+		t.Fatalf("errors.Unwrap(errors.Unwrap(err)) = %#v, want %#v", unwrapped, testMicroErr)
+	}
+}
+
+func Test_MaskSkip(t *testing.T) {
+	// maskHelper masks err on behalf of its caller.
+	maskHelper := func(err error) error {
+		return MaskSkip(1, err)
+	}
+	maskfHelper := func(err *Error) error {
+		return MaskfSkip(1, err, "test %s", "annotation")
+	}
+
+	if MaskSkip(0, nil) != nil {
+		t.Fatalf("expected nil")
+	}
+
+	_, _, line, _ := runtime.Caller(0)
+	err := MaskSkip(0, testMicroErr)
+	helperErr := maskHelper(testMicroErr)
+	maskfHelperErr := maskfHelper(testMicroErr)
+
+	testCases := []struct {
+		name         string
+		inputError   error
+		expectedLine int
+	}{
+		{
+			name:         "case 0: skip=0",
+			inputError:   err,
+			expectedLine: line + 1,
+		},
+		{
+			name:         "case 1: skip=1 from helper",
+			inputError:   helperErr,
+			expectedLine: line + 2,
+		},
+		{
+			name:         "case 2: MaskfSkip skip=1 from helper",
+			inputError:   maskfHelperErr,
+			expectedLine: line + 3,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			stack := Stack(tc.inputError)
+			if len(stack) != 1 {
+				t.Fatalf("expected 1 stack entry got %d", len(stack))
+			}
+			if stack[0].Line != tc.expectedLine {
+				t.Fatalf("expected line %d got %d", tc.expectedLine, stack[0].Line)
+			}
+			if !errors.Is(tc.inputError, testMicroErr) {
+				t.Fatalf("expected error to match testMicroErr")
+			}
+		})
+	}
+}
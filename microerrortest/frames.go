@@ -0,0 +1,55 @@
+package microerrortest
+
+import (
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+)
+
+// SequentialFrames makes errors masked during the test record synthetic
+// frames numbered in the order errors are masked, i.e. "frame:1",
+// "frame:2" and so on, instead of their real file and line. Rendered errors
+// are then independent of where they were masked. The frames recorded
+// before are recorded again when the test finishes. Errors may be masked by
+// other goroutines meanwhile, but it must not be used in parallel tests as
+// it changes global state.
+func SequentialFrames(t testing.TB) {
+	t.Helper()
+
+	var n int64
+	setFrameFunc(t, func(pc uintptr) (string, int) {
+		return "frame", int(atomic.AddInt64(&n, 1))
+	})
+}
+
+// SymbolicFrames makes errors masked during the test record the name of
+// the masking function, e.g. "pkg.(*Type).Method" with line 0, instead of
+// their real file and line. Rendered errors are then independent of line
+// shifts within functions. The frames recorded before are recorded again
+// when the test finishes. Errors may be masked by other goroutines
+// meanwhile, but it must not be used in parallel tests as it changes global
+// state.
+func SymbolicFrames(t testing.TB) {
+	t.Helper()
+
+	setFrameFunc(t, func(pc uintptr) (string, int) {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+		name := frame.Function
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			name = name[i+1:]
+		}
+
+		return name, 0
+	})
+}
+
+func setFrameFunc(t testing.TB, f microerror.FrameFunc) {
+	previous := microerror.SetFrameFunc(f)
+	t.Cleanup(func() {
+		microerror.SetFrameFunc(previous)
+	})
+}
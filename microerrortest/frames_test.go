@@ -0,0 +1,106 @@
+package microerrortest
+
+import (
+	"errors"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+)
+
+func maskTwice(err error) error {
+	err = microerror.Mask(err)
+	return microerror.Mask(err)
+}
+
+func Test_Frames(t *testing.T) {
+	testCases := []struct {
+		name          string
+		framesFunc    func(t testing.TB)
+		expectedStack []microerror.StackEntry
+	}{
+		{
+			name:       "case 0: sequential frames",
+			framesFunc: SequentialFrames,
+			expectedStack: []microerror.StackEntry{
+				{File: "frame", Line: 1},
+				{File: "frame", Line: 2},
+				{File: "frame", Line: 3},
+			},
+		},
+		{
+			name:       "case 1: symbolic frames",
+			framesFunc: SymbolicFrames,
+			expectedStack: []microerror.StackEntry{
+				{File: "microerrortest.maskTwice", Line: 0},
+				{File: "microerrortest.maskTwice", Line: 0},
+				{File: "microerrortest.Test_Frames.func1.1.1", Line: 0},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			t.Run("frames", func(t *testing.T) {
+				tc.framesFunc(t)
+
+				err := func() error {
+					return microerror.Mask(maskTwice(errors.New("test error")))
+				}()
+
+				stack := microerror.Stack(err)
+				if len(stack) != len(tc.expectedStack) {
+					t.Fatalf("expected %d stack entries got %d", len(tc.expectedStack), len(stack))
+				}
+				for i, e := range stack {
+					if e.File != tc.expectedStack[i].File || e.Line != tc.expectedStack[i].Line {
+						t.Fatalf("expected stack entry %d %s:%d got %s:%d", i, tc.expectedStack[i].File, tc.expectedStack[i].Line, e.File, e.Line)
+					}
+				}
+			})
+
+			// Real frames are recorded again after the test finished.
+			stack := microerror.Stack(microerror.Mask(errors.New("test error")))
+			if filepath.Base(stack[0].File) != "frames_test.go" {
+				t.Fatalf("expected real frame got %s:%d", stack[0].File, stack[0].Line)
+			}
+		})
+	}
+}
+
+func Test_Frames_Nested(t *testing.T) {
+	SymbolicFrames(t)
+
+	// Errors masked concurrently must not race with installing and
+	// restoring frame funcs.
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				_ = microerror.Mask(errors.New("test error"))
+			}
+		}
+	}()
+	defer func() {
+		close(done)
+		<-stopped
+	}()
+
+	t.Run("sequential", func(t *testing.T) {
+		SequentialFrames(t)
+	})
+
+	// The symbolic frames are recorded again after the nested test finished.
+	stack := microerror.Stack(microerror.Mask(errors.New("test error")))
+	if stack[0].File != "microerrortest.Test_Frames_Nested" {
+		t.Fatalf("expected symbolic frame got %s:%d", stack[0].File, stack[0].Line)
+	}
+}
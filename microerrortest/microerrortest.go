@@ -0,0 +1,122 @@
+// Package microerrortest provides test assertions for errors created with
+// microerror. All assertions stop the test on failure and print the failing
+// error with microerror.Pretty including its stack.
+package microerrortest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+)
+
+var (
+	filePattern = regexp.MustCompile(`("file"\s*:\s*")\S+(/[^/"]+\.go")`)
+	linePattern = regexp.MustCompile(`("line"\s*:\s*)\d+`)
+)
+
+// AssertKind asserts err matches kind, see errors.Is.
+func AssertKind(t testing.TB, err error, kind *microerror.Error) {
+	t.Helper()
+
+	if !errors.Is(err, kind) {
+		t.Fatalf("expected error of kind %#q, got:\n%s", kind.Kind, pretty(err))
+	}
+}
+
+// AssertAnnotation asserts the annotation of err, i.e. of the outermost
+// error created with microerror.Maskf or microerror.Wrapf, equals
+// annotation.
+func AssertAnnotation(t testing.TB, err error, annotation string) {
+	t.Helper()
+
+	v := microerror.NewView(err)
+	if !v.Annotated || v.Annotation != annotation {
+		t.Fatalf("expected error with annotation %q, got:\n%s", annotation, pretty(err))
+	}
+}
+
+// AssertStackContains asserts err was masked at line of file. File is
+// matched against the end of the recorded paths, so "file.go" and
+// "pkg/file.go" both match "/src/pkg/file.go".
+func AssertStackContains(t testing.TB, err error, file string, line int) {
+	t.Helper()
+
+	for _, e := range microerror.Stack(err) {
+		if e.Line == line && (e.File == file || strings.HasSuffix(e.File, "/"+file)) {
+			return
+		}
+	}
+
+	t.Fatalf("expected error masked at %s:%d, got:\n%s", file, line, pretty(err))
+}
+
+// AssertNoError asserts err is nil.
+func AssertNoError(t testing.TB, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("expected no error, got:\n%s", pretty(err))
+	}
+}
+
+// AssertJSONGolden asserts microerror.JSON of err, indented and
+// normalized with NormalizeJSON, equals the content of the golden file.
+// When update is true the golden file is written first. Tests usually pass
+// the value of their own -update flag.
+func AssertJSONGolden(t testing.TB, err error, golden string, update bool) {
+	t.Helper()
+
+	b := &bytes.Buffer{}
+	indentErr := json.Indent(b, []byte(microerror.JSON(err)), "", "\t")
+	if indentErr != nil {
+		t.Fatal(indentErr)
+	}
+	b.WriteString("\n")
+	actual := NormalizeJSON(b.String())
+
+	if update {
+		writeErr := os.MkdirAll(filepath.Dir(golden), 0755) //nolint:gosec
+		if writeErr != nil {
+			t.Fatal(writeErr)
+		}
+		writeErr = os.WriteFile(golden, []byte(actual), 0644) //nolint:gosec
+		if writeErr != nil {
+			t.Fatal(writeErr)
+		}
+	}
+
+	expected, readErr := os.ReadFile(golden) // nolint:gosec
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+
+	if actual != string(expected) {
+		t.Fatalf("JSON of error does not match %s\n\nexpected:\n%s\ngot:\n%s", golden, expected, actual)
+	}
+}
+
+// NormalizeJSON makes the output of microerror.JSON independent of the
+// machine and the exact lines errors were masked at. Paths like
+// "/home/user/src/pkg/file.go" are replaced with "--REPLACED--/file.go" and
+// lines with 0.
+func NormalizeJSON(s string) string {
+	s = filePattern.ReplaceAllString(s, "$1--REPLACED--$2")
+	s = linePattern.ReplaceAllString(s, "${1}0")
+
+	return s
+}
+
+func pretty(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+
+	return microerror.Pretty(err, true)
+}
@@ -0,0 +1,191 @@
+package microerrortest
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+)
+
+var update = flag.Bool("update", false, "update .golden files")
+
+var (
+	testMicroErr = &microerror.Error{
+		Kind: "testKind",
+	}
+	otherMicroErr = &microerror.Error{
+		Kind: "otherKind",
+	}
+)
+
+// fakeTB records failures. Fatal failures stop the goroutine running the
+// assertion like they do with testing.T.
+type fakeTB struct {
+	testing.TB
+
+	failure string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Fatal(args ...interface{}) {
+	f.failure = fmt.Sprint(args...)
+	runtime.Goexit()
+}
+
+func (f *fakeTB) Fatalf(format string, args ...interface{}) {
+	f.failure = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+// run runs assert with a fakeTB and returns the recorded failure.
+func run(assert func(t testing.TB)) string {
+	f := &fakeTB{}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert(f)
+	}()
+	wg.Wait()
+
+	return f.failure
+}
+
+func Test_Assert(t *testing.T) {
+	_, _, line, _ := runtime.Caller(0)
+	maskedErr := microerror.Mask(microerror.Maskf(testMicroErr, "test annotation"))
+
+	testCases := []struct {
+		name            string
+		assert          func(t testing.TB)
+		expectedFailure string
+	}{
+		{
+			name: "case 0: AssertKind matching",
+			assert: func(t testing.TB) {
+				AssertKind(t, maskedErr, testMicroErr)
+			},
+		},
+		{
+			name: "case 1: AssertKind not matching",
+			assert: func(t testing.TB) {
+				AssertKind(t, maskedErr, otherMicroErr)
+			},
+			expectedFailure: "expected error of kind `otherKind`, got:\nTest kind: test annotation\n",
+		},
+		{
+			name: "case 2: AssertKind nil",
+			assert: func(t testing.TB) {
+				AssertKind(t, nil, testMicroErr)
+			},
+			expectedFailure: "expected error of kind `testKind`, got:\n<nil>",
+		},
+		{
+			name: "case 3: AssertAnnotation matching",
+			assert: func(t testing.TB) {
+				AssertAnnotation(t, maskedErr, "test annotation")
+			},
+		},
+		{
+			name: "case 4: AssertAnnotation not matching",
+			assert: func(t testing.TB) {
+				AssertAnnotation(t, maskedErr, "other annotation")
+			},
+			expectedFailure: "expected error with annotation \"other annotation\", got:\nTest kind: test annotation\n",
+		},
+		{
+			name: "case 5: AssertAnnotation not annotated",
+			assert: func(t testing.TB) {
+				AssertAnnotation(t, microerror.Mask(testMicroErr), "")
+			},
+			expectedFailure: "expected error with annotation \"\", got:\nTest kind\n",
+		},
+		{
+			name: "case 6: AssertStackContains matching file name",
+			assert: func(t testing.TB) {
+				AssertStackContains(t, maskedErr, "microerrortest_test.go", line+1)
+			},
+		},
+		{
+			name: "case 7: AssertStackContains matching path suffix",
+			assert: func(t testing.TB) {
+				AssertStackContains(t, maskedErr, "microerrortest/microerrortest_test.go", line+1)
+			},
+		},
+		{
+			name: "case 8: AssertStackContains not matching",
+			assert: func(t testing.TB) {
+				AssertStackContains(t, maskedErr, "other_test.go", line+1)
+			},
+			expectedFailure: "expected error masked at other_test.go:" + strconv.Itoa(line+1) + ", got:\nTest kind: test annotation\n",
+		},
+		{
+			name: "case 9: AssertNoError nil",
+			assert: func(t testing.TB) {
+				AssertNoError(t, nil)
+			},
+		},
+		{
+			name: "case 10: AssertNoError not nil",
+			assert: func(t testing.TB) {
+				AssertNoError(t, maskedErr)
+			},
+			expectedFailure: "expected no error, got:\nTest kind: test annotation\n",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			failure := run(tc.assert)
+
+			// Only compare the message, not the stack.
+			if tc.expectedFailure != "" && strings.HasPrefix(failure, tc.expectedFailure) {
+				return
+			}
+			if failure != tc.expectedFailure {
+				t.Fatalf("expected failure %q got %q", tc.expectedFailure, failure)
+			}
+		})
+	}
+}
+
+// Test_AssertJSONGolden tests AssertJSONGolden with its own golden file.
+//
+//	go test ./microerrortest -run Test_AssertJSONGolden -update
+func Test_AssertJSONGolden(t *testing.T) {
+	err := microerror.Mask(microerror.Wrapf(testMicroErr, microerror.Mask(errors.New("test error")), "test annotation"))
+
+	AssertJSONGolden(t, err, filepath.Join("testdata", "json.golden"), *update)
+
+	// Golden files are independent of the lines errors were masked at.
+	err = microerror.Mask(microerror.Wrapf(testMicroErr, microerror.Mask(errors.New("test error")), "test annotation"))
+	AssertJSONGolden(t, err, filepath.Join("testdata", "json.golden"), false)
+
+	failure := run(func(t testing.TB) {
+		AssertJSONGolden(t, testMicroErr, filepath.Join("testdata", "json.golden"), false)
+	})
+	if !strings.HasPrefix(failure, "JSON of error does not match testdata/json.golden") {
+		t.Fatalf("expected failure, got %q", failure)
+	}
+}
+
+func Test_NormalizeJSON(t *testing.T) {
+	input := `{"stack":[{"file":"/home/user/src/pkg/file.go","line":42},{"file": "C:/src/other.go", "line": 7}]}`
+	expected := `{"stack":[{"file":"--REPLACED--/file.go","line":0},{"file": "--REPLACED--/other.go", "line": 0}]}`
+
+	actual := NormalizeJSON(input)
+	if actual != expected {
+		t.Fatalf("expected %q got %q", expected, actual)
+	}
+}
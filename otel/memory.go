@@ -0,0 +1,49 @@
+package otel
+
+import "sync"
+
+// Event is a span event recorded by MemorySpan.
+type Event struct {
+	Name       string
+	Attributes []Attribute
+}
+
+// MemorySpan is a Span keeping everything recorded in memory. It is meant
+// to be used in tests and is safe for concurrent use.
+type MemorySpan struct {
+	mu                sync.Mutex
+	events            []Event
+	statusCode        StatusCode
+	statusDescription string
+}
+
+func (s *MemorySpan) AddEvent(name string, attributes ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, Event{Name: name, Attributes: attributes})
+}
+
+func (s *MemorySpan) SetStatus(code StatusCode, description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.statusCode = code
+	s.statusDescription = description
+}
+
+// Events returns a copy of all events added to s.
+func (s *MemorySpan) Events() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Event(nil), s.events...)
+}
+
+// Status returns the last status set on s.
+func (s *MemorySpan) Status() (StatusCode, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.statusCode, s.statusDescription
+}
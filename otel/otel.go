@@ -0,0 +1,117 @@
+// Package otel records microerror errors on OpenTelemetry compatible spans
+// as exception events following the OpenTelemetry semantic conventions. It
+// does not depend on the OpenTelemetry SDK. Instead it defines the minimal
+// Span interface it needs, which is easily implemented on top of
+// trace.Span.
+package otel
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// EventName is the name of the span event recording an error.
+	EventName = "exception"
+
+	AttributeExceptionType       = "exception.type"
+	AttributeExceptionMessage    = "exception.message"
+	AttributeExceptionStacktrace = "exception.stacktrace"
+)
+
+// StatusCode is the status of a span. The values match the ones of the
+// OpenTelemetry codes package.
+type StatusCode uint32
+
+const (
+	StatusUnset StatusCode = 0
+	StatusError StatusCode = 1
+	StatusOK    StatusCode = 2
+)
+
+// Attribute is a key value pair attached to a span event.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Span is the subset of an OpenTelemetry span needed to record errors.
+type Span interface {
+	AddEvent(name string, attributes ...Attribute)
+	SetStatus(code StatusCode, description string)
+}
+
+// RecordError adds an exception event describing err to span and sets the
+// span status to StatusError. It does nothing when err is nil.
+func RecordError(span Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.AddEvent(EventName, Attributes(err)...)
+	span.SetStatus(StatusError, err.Error())
+}
+
+// Attributes returns the semantic convention attributes of an exception
+// event describing err. The exception type is the kind of err or the Go
+// type of errors not created with microerror. The stack trace is only set
+// for masked errors.
+func Attributes(err error) []Attribute {
+	if err == nil {
+		return nil
+	}
+
+	attributes := []Attribute{
+		{Key: AttributeExceptionType, Value: exceptionType(err)},
+		{Key: AttributeExceptionMessage, Value: err.Error()},
+	}
+
+	stack := microerror.Stack(err)
+	if len(stack) > 0 {
+		attributes = append(attributes, Attribute{Key: AttributeExceptionStacktrace, Value: formatStack(stack)})
+	}
+
+	return attributes
+}
+
+func exceptionType(err error) string {
+	var eerr *microerror.Error
+	if errors.As(err, &eerr) {
+		return eerr.Kind
+	}
+
+	// Skip the masking layers to find the type of the original error.
+	for {
+		_, masked := err.(interface{ StackTrace() []uintptr })
+		if !masked {
+			break
+		}
+		err = errors.Unwrap(err)
+	}
+
+	return fmt.Sprintf("%T", err)
+}
+
+// formatStack renders stack similar to Go panics with the function on one
+// line followed by its indented location.
+func formatStack(stack []microerror.StackEntry) string {
+	var builder strings.Builder
+	for i, e := range stack {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		f := runtime.FuncForPC(e.PC)
+		if f != nil {
+			builder.WriteString(f.Name())
+			builder.WriteString("\n")
+		}
+		fmt.Fprintf(&builder, "\t%s:%d", e.File, e.Line)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,120 @@
+package otel
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/microerror"
+)
+
+var testMicroErr = &microerror.Error{
+	Kind: "testKind",
+}
+
+func Test_RecordError(t *testing.T) {
+	testCases := []struct {
+		name               string
+		inputErrorFunc     func() error
+		expectedEvents     []Event
+		expectedStatusCode StatusCode
+	}{
+		{
+			name: "case 0: nil",
+			inputErrorFunc: func() error {
+				return nil
+			},
+			expectedEvents:     nil,
+			expectedStatusCode: StatusUnset,
+		},
+		{
+			name: "case 1: error=microerror.Error no masking",
+			inputErrorFunc: func() error {
+				return testMicroErr
+			},
+			expectedEvents: []Event{
+				{
+					Name: "exception",
+					Attributes: []Attribute{
+						{Key: "exception.type", Value: "testKind"},
+						{Key: "exception.message", Value: "test kind"},
+					},
+				},
+			},
+			expectedStatusCode: StatusError,
+		},
+		{
+			name: "case 2: error=microerror.Error depth=2 Maskf",
+			inputErrorFunc: func() error {
+				err := microerror.Maskf(testMicroErr, "test annotation")
+				err = microerror.Mask(err)
+				return err
+			},
+			expectedEvents: []Event{
+				{
+					Name: "exception",
+					Attributes: []Attribute{
+						{Key: "exception.type", Value: "testKind"},
+						{Key: "exception.message", Value: "test kind: test annotation"},
+						{Key: "exception.stacktrace", Value: "github.com/giantswarm/microerror/otel.Test_RecordError.func3\n\t--REPLACED--/otel_test.go:53\ngithub.com/giantswarm/microerror/otel.Test_RecordError.func3\n\t--REPLACED--/otel_test.go:54"},
+					},
+				},
+			},
+			expectedStatusCode: StatusError,
+		},
+		{
+			name: "case 3: error=fmt.Errorf depth=1 Mask",
+			inputErrorFunc: func() error {
+				return microerror.Mask(fmt.Errorf("test wrapper: %w", errors.New("test error")))
+			},
+			expectedEvents: []Event{
+				{
+					Name: "exception",
+					Attributes: []Attribute{
+						{Key: "exception.type", Value: "*fmt.wrapError"},
+						{Key: "exception.message", Value: "test wrapper: test error"},
+						{Key: "exception.stacktrace", Value: "github.com/giantswarm/microerror/otel.Test_RecordError.func4\n\t--REPLACED--/otel_test.go:72"},
+					},
+				},
+			},
+			expectedStatusCode: StatusError,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			span := &MemorySpan{}
+			err := tc.inputErrorFunc()
+			RecordError(span, err)
+
+			events := span.Events()
+			// Change paths to avoid prefixes like
+			// "/Users/username/go/src/" so this can test can be
+			// executed on different machines.
+			r := regexp.MustCompile(`\t\S+(/[^/]+\.go:\d+)`)
+			for _, e := range events {
+				for j, a := range e.Attributes {
+					e.Attributes[j].Value = r.ReplaceAllString(a.Value, "\t--REPLACED--$1")
+				}
+			}
+
+			if !cmp.Equal(events, tc.expectedEvents) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedEvents, events))
+			}
+
+			code, description := span.Status()
+			if code != tc.expectedStatusCode {
+				t.Fatalf("status code = %v, want %v", code, tc.expectedStatusCode)
+			}
+			if err != nil && description != err.Error() {
+				t.Fatalf("status description = %#q, want %#q", description, err.Error())
+			}
+		})
+	}
+}
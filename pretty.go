@@ -1,9 +1,7 @@
 package microerror
 
 import (
-	"errors"
 	"strings"
-	"unicode"
 )
 
 const (
@@ -11,43 +9,25 @@ const (
 	delimiter = ": "
 )
 
+// Pretty renders err with PrettyTemplate or, when stackTrace is true,
+// PrettyStackTemplate.
 func Pretty(err error, stackTrace bool) string {
-	var message strings.Builder
-
-	// Check if it's an annotated error.
-	var aErr *annotatedError
-	if errors.As(err, &aErr) {
-		capitalizeAnnotation := true
-
-		if aErr.underlying.Kind != kindNil && aErr.underlying.Kind != kindUnknown {
-			message.WriteString(prettifyErrorMessage(aErr.underlying.Error(), true))
-			message.WriteString(delimiter)
-			capitalizeAnnotation = false
-		}
-		message.WriteString(prettifyErrorMessage(aErr.annotation, capitalizeAnnotation))
-	} else {
-		// This is either an unmasked microerror, or
-		// a simple 'errors.New()' error.
-		pretty := prettifyErrorMessage(err.Error(), true)
-		if len(pretty) < 1 {
-			return ""
-		}
-		message.WriteString(pretty)
+	tmpl := prettyTemplate
+	if stackTrace {
+		tmpl = prettyStackTemplate
 	}
 
-	if stackTrace {
-		// Add formatted stack trace.
-		if sErr, ok := err.(*stackedError); ok {
-			message.WriteString("\n")
-			trace := createStackTrace(sErr)
-			message.WriteString(formatStackTrace(trace))
-		}
+	message, err := Render(err, tmpl)
+	if err != nil {
+		panic(err.Error())
 	}
 
-	return message.String()
+	return message
 }
 
-func prettifyErrorMessage(message string, capitalize bool) string {
+// prettify removes the "error: " prefix and the " error" suffix of
+// message.
+func prettify(message string) string {
 	if len(message) < 1 {
 		return message
 	}
@@ -60,12 +40,5 @@ func prettifyErrorMessage(message string, capitalize bool) string {
 	// without annotations.
 	message = strings.TrimSuffix(message, " error")
 
-	if capitalize {
-		// Capitalize the first letter.
-		tmpMessage := []rune(message)
-		tmpMessage[0] = unicode.ToUpper(tmpMessage[0])
-		message = string(tmpMessage)
-	}
-
 	return message
 }
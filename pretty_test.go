@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"testing"
+	"time"
 )
 
 // This test uses golden files.
@@ -17,6 +18,7 @@ func TestPretty(t *testing.T) {
 		name               string
 		errorFactory       func() error
 		stackTrace         bool
+		collapseStack      bool
 		expectedGoldenFile string
 	}{
 		{
@@ -192,10 +194,80 @@ func TestPretty(t *testing.T) {
 			stackTrace:         true,
 			expectedGoldenFile: "pretty-microerror-10-depth-stack-trace.golden",
 		},
+		{
+			name: "case 15: microerror, 10 depth, with collapsed stack trace",
+			errorFactory: func() error {
+				err := &Error{
+					Kind: "somethingWentWrongError",
+				}
+
+				// Let's build up this stack trace.
+				newErr := Mask(err)
+				for i := 0; i < 10; i++ {
+					newErr = Mask(newErr)
+				}
+
+				return newErr
+			},
+			stackTrace:         true,
+			collapseStack:      true,
+			expectedGoldenFile: "pretty-microerror-10-depth-collapsed-stack-trace.golden",
+		},
+		{
+			name: "case 16: microerror, cycle, with collapsed stack trace",
+			errorFactory: func() error {
+				err := &Error{
+					Kind: "somethingWentWrongError",
+				}
+
+				newErr := Mask(err)
+				for i := 0; i < 5; i++ {
+					newErr = Mask(newErr)
+					newErr = Mask(newErr)
+				}
+
+				return newErr
+			},
+			stackTrace:         true,
+			collapseStack:      true,
+			expectedGoldenFile: "pretty-microerror-cycle-collapsed-stack-trace.golden",
+		},
+		{
+			name: "case 17: microerror, 3 depth, with times, with stack trace",
+			errorFactory: func() error {
+				SetClock(newTestClock(1500 * time.Millisecond))
+				defer SetClock(nil)
+
+				err := &Error{
+					Kind: "somethingWentWrongError",
+				}
+
+				newErr := Mask(err)
+				newErr = Mask(newErr)
+				newErr = Mask(newErr)
+
+				return newErr
+			},
+			stackTrace:         true,
+			expectedGoldenFile: "pretty-microerror-3-depth-time-stack-trace.golden",
+		},
+		{
+			name: "case 18: masked simple empty error, with stack trace",
+			errorFactory: func() error {
+				err := errors.New("")
+
+				return Mask(err)
+			},
+			stackTrace:         true,
+			expectedGoldenFile: "pretty-masked-simple-empty-error-stack-trace.golden",
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			SetStackCollapsing(tc.collapseStack)
+			defer SetStackCollapsing(false)
+
 			err := tc.errorFactory()
 			message := Pretty(err, tc.stackTrace)
 
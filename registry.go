@@ -0,0 +1,147 @@
+package microerror
+
+import (
+	"strings"
+	"sync"
+)
+
+// Registry holds known errors so they can be found again by their kind,
+// their code or the messages they produced. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	codes    map[string]*Error
+	kinds    map[string]*Error
+	messages map[string]*Error
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		codes:    map[string]*Error{},
+		kinds:    map[string]*Error{},
+		messages: map[string]*Error{},
+	}
+}
+
+// Register adds errs to the registry. When several errors share the same
+// kind, which is common across packages, the first one registered wins.
+// Codes on the other hand must be unique. Nothing is registered when any of
+// errs is invalid.
+func (r *Registry) Register(errs ...*Error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	codes := map[string]*Error{}
+	for _, e := range errs {
+		if e == nil || e.Kind == "" {
+			return Maskf(invalidRegistrationError, "kind must not be empty")
+		}
+		if e.Code == "" {
+			continue
+		}
+
+		for _, m := range []map[string]*Error{r.codes, codes} {
+			o, ok := m[e.Code]
+			if ok && o != e {
+				return Maskf(invalidRegistrationError, "code %#q of kind %#q is already registered for kind %#q", e.Code, e.Kind, o.Kind)
+			}
+		}
+		codes[e.Code] = e
+	}
+
+	for c, e := range codes {
+		r.codes[c] = e
+	}
+	for _, e := range errs {
+		if _, ok := r.kinds[e.Kind]; !ok {
+			r.kinds[e.Kind] = e
+		}
+
+		// Messages formatted by LowerCase and by the formatters splitting
+		// kinds into words may differ, e.g. for "kubeAPIServerB".
+		for _, m := range []string{messageKey(e.Kind), messageKey(toStringCase(e.Kind))} {
+			if _, ok := r.messages[m]; !ok {
+				r.messages[m] = e
+			}
+		}
+	}
+
+	return nil
+}
+
+// MustRegister is like Register but panics on error. It is meant to be used
+// in package level variable declarations and init functions.
+func (r *Registry) MustRegister(errs ...*Error) {
+	err := r.Register(errs...)
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+// ByCode returns the error registered with code or nil when there is none.
+func (r *Registry) ByCode(code string) *Error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.codes[code]
+}
+
+// Lookup returns the registered error of kind.
+func (r *Registry) Lookup(kind string) (*Error, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.kinds[kind]
+	return e, ok
+}
+
+// ParseMessage maps a message produced by Error() back to a registered
+// error. Messages look like "<kind words>" or "<kind words>: <annotation>"
+// where the kind words may be formatted by any of the KindFormatter
+// implementations of this package. Messages prefixed with a code, see
+// SetCodesInMessages, are looked up by their code first. The annotation is
+// returned as is.
+func (r *Registry) ParseMessage(msg string) (*Error, string, bool) {
+	code, msg, hasCode := cutCode(msg)
+	words, annotation, _ := strings.Cut(msg, delimiter)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if hasCode {
+		e, ok := r.codes[code]
+		if ok {
+			return e, annotation, true
+		}
+	}
+
+	e, ok := r.messages[messageKey(words)]
+	if !ok {
+		return nil, "", false
+	}
+
+	return e, annotation, true
+}
+
+var defaultRegistry = NewRegistry()
+
+// Register adds errs to the default registry. See Registry.Register.
+func Register(errs ...*Error) error {
+	return defaultRegistry.Register(errs...)
+}
+
+// MustRegister adds errs to the default registry. See Registry.MustRegister.
+func MustRegister(errs ...*Error) {
+	defaultRegistry.MustRegister(errs...)
+}
+
+// ParseMessage maps msg back to an error of the default registry. See
+// Registry.ParseMessage.
+func ParseMessage(msg string) (*Error, string, bool) {
+	return defaultRegistry.ParseMessage(msg)
+}
+
+// messageKey normalizes kinds and messages formatted by any KindFormatter of
+// this package to their lower case words separated by spaces.
+func messageKey(s string) string {
+	return LowerCaseWords(s)
+}
@@ -0,0 +1,111 @@
+package microerror
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func Test_Registry_ParseMessage(t *testing.T) {
+	invalidHTTPConfigError := &Error{
+		Kind: "invalidHTTPConfigError",
+	}
+	kubeAPIServerError := &Error{
+		Kind: "kube-apiserverNotReadyB",
+	}
+	duplicateTestMicroErr := &Error{
+		Kind: testMicroErr.Kind,
+	}
+
+	r := NewRegistry()
+	r.MustRegister(testMicroErr, invalidHTTPConfigError, duplicateTestMicroErr, kubeAPIServerError)
+
+	testCases := []struct {
+		name               string
+		inputMessage       string
+		expectedError      *Error
+		expectedAnnotation string
+		expectedOK         bool
+	}{
+		{
+			name:          "case 0: kind only",
+			inputMessage:  Mask(testMicroErr).Error(),
+			expectedError: testMicroErr,
+			expectedOK:    true,
+		},
+		{
+			name:               "case 1: kind with annotation",
+			inputMessage:       Maskf(testMicroErr, "test annotation").Error(),
+			expectedError:      testMicroErr,
+			expectedAnnotation: "test annotation",
+			expectedOK:         true,
+		},
+		{
+			name:               "case 2: annotation containing delimiter",
+			inputMessage:       Maskf(invalidHTTPConfigError, "field %#q: must not be empty", "name").Error(),
+			expectedError:      invalidHTTPConfigError,
+			expectedAnnotation: "field `name`: must not be empty",
+			expectedOK:         true,
+		},
+		{
+			name:          "case 3: kind formatted with another formatter",
+			inputMessage:  SnakeCase(invalidHTTPConfigError.Kind),
+			expectedError: invalidHTTPConfigError,
+			expectedOK:    true,
+		},
+		{
+			name:          "case 4: kind formatted with the default formatter",
+			inputMessage:  "kube-apiserver not readyb",
+			expectedError: kubeAPIServerError,
+			expectedOK:    true,
+		},
+		{
+			name:          "case 5: kind split into words",
+			inputMessage:  LowerCaseWords(kubeAPIServerError.Kind),
+			expectedError: kubeAPIServerError,
+			expectedOK:    true,
+		},
+		{
+			name:         "case 6: unknown kind",
+			inputMessage: "something went wrong: test annotation",
+			expectedOK:   false,
+		},
+		{
+			name:         "case 7: foreign error",
+			inputMessage: fmt.Errorf("wrapped: %w", errors.New("test error")).Error(),
+			expectedOK:   false,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			e, annotation, ok := r.ParseMessage(tc.inputMessage)
+			if ok != tc.expectedOK {
+				t.Fatalf("ok = %v, want %v", ok, tc.expectedOK)
+			}
+			if e != tc.expectedError {
+				t.Fatalf("err = %#v, want %#v", e, tc.expectedError)
+			}
+			if annotation != tc.expectedAnnotation {
+				t.Fatalf("annotation = %#q, want %#q", annotation, tc.expectedAnnotation)
+			}
+		})
+	}
+}
+
+func Test_Registry_Register_Invalid(t *testing.T) {
+	r := NewRegistry()
+
+	err := r.Register(testMicroErr, &Error{})
+	if !IsInvalidRegistration(err) {
+		t.Fatalf("expected invalid registration error, got %v", err)
+	}
+
+	_, ok := r.Lookup(testMicroErr.Kind)
+	if ok {
+		t.Fatalf("expected no error to be registered")
+	}
+}
@@ -0,0 +1,137 @@
+package microerror
+
+import (
+	"errors"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// PrettyTemplate is the template used by Pretty without stack trace. It can
+// be parsed with TemplateFuncs and extended to customize the output.
+const PrettyTemplate = `{{ if .Code }}{{ codePrefix .Code }}{{ end }}
+{{- if .Annotated }}
+	{{- if .Title }}{{ .Title | prettify | capitalize }}: {{ .Annotation | prettify }}
+	{{- else }}{{ .Annotation | prettify | capitalize }}{{ end }}
+	{{- with .Causes }}{{ if $.Annotation }}: {{ end }}{{ (index . 0).Message | prettify }}{{ end }}
+{{- else }}{{ .Title | prettify | capitalize }}{{ end }}`
+
+// PrettyStackTemplate is the template used by Pretty with stack trace. The
+// stack is omitted when the message is empty.
+const PrettyStackTemplate = PrettyTemplate + `{{ if and .Stack (or .Code .Annotated (.Title | prettify)) }}
+{{ stack .Stack }}{{ end }}`
+
+var (
+	prettyTemplate      = template.Must(template.New("pretty").Funcs(TemplateFuncs()).Parse(PrettyTemplate))
+	prettyStackTemplate = template.Must(template.New("pretty").Funcs(TemplateFuncs()).Parse(PrettyStackTemplate))
+)
+
+// View is the data passed to templates executed by Render.
+type View struct {
+	// Code is the code of the Error of err, see Error.Code.
+	Code string
+	// Kind is the kind of the Error of err. It is "unknown" for errors
+	// not created with this package.
+	Kind string
+	// Title is the message of Kind as returned by Error() without the
+	// code, e.g. "not found error". Errors not created with Maskf or
+	// Wrapf use their whole message. It is empty for errors created with
+	// Maskf or Wrapf of kind "nil" or "unknown" so their annotation can
+	// take its place.
+	Title string
+	Desc  string
+	Docs  string
+	// Annotated is true when err was created with Maskf or Wrapf.
+	Annotated  bool
+	Annotation string
+	// Stack holds the stack entries when err was returned by one of the
+	// masking functions of this package.
+	Stack []StackEntry
+	// Causes holds the layers of the cause set with Wrapf, outermost
+	// first. See Layers.
+	Causes []Layer
+	// Fields holds the fields of err. See Fields.
+	Fields map[string]string
+}
+
+// NewView builds the View of err.
+func NewView(err error) View {
+	var v View
+	if err == nil {
+		return v
+	}
+
+	v.Kind = kindUnknown
+	var eErr *Error
+	if errors.As(err, &eErr) {
+		v.Code = eErr.Code
+		v.Kind = eErr.Kind
+		v.Desc = eErr.Desc
+		v.Docs = eErr.Docs
+	}
+
+	var aErr *annotatedError
+	if errors.As(err, &aErr) {
+		v.Annotated = true
+		v.Annotation = aErr.annotation
+		if aErr.underlying.Kind != kindNil && aErr.underlying.Kind != kindUnknown {
+			v.Title = aErr.underlying.message()
+		}
+		if aErr.cause != nil {
+			v.Causes = Layers(aErr.cause)
+		}
+	} else {
+		v.Title = err.Error()
+		if v.Code != "" {
+			v.Title = strings.TrimPrefix(v.Title, codePrefix(v.Code))
+		}
+	}
+
+	if sErr, ok := err.(*stackedError); ok {
+		v.Stack = createStackTrace(sErr)
+		if collapseStacks {
+			v.Stack = collapseStack(v.Stack)
+		}
+	}
+
+	v.Fields = Fields(err)
+
+	return v
+}
+
+// Render executes tmpl with the View of err. Templates parsed with
+// TemplateFuncs can use the helpers Pretty uses to format its output.
+func Render(err error, tmpl *template.Template) (string, error) {
+	var builder strings.Builder
+	execErr := tmpl.Execute(&builder, NewView(err))
+	if execErr != nil {
+		return "", Mask(execErr)
+	}
+
+	return builder.String(), nil
+}
+
+// TemplateFuncs returns the functions available in PrettyTemplate:
+//
+//   - capitalize upper cases the first letter.
+//   - codePrefix formats a code as "[code] ".
+//   - prettify removes the "error: " prefix and the " error" suffix.
+//   - stack formats stack entries one per line.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"capitalize": capitalize,
+		"codePrefix": codePrefix,
+		"prettify":   prettify,
+		"stack":      formatStackTrace,
+	}
+}
+
+func capitalize(s string) string {
+	if len(s) < 1 {
+		return s
+	}
+
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
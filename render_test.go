@@ -0,0 +1,180 @@
+package microerror
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"text/template"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func Test_Render(t *testing.T) {
+	testCases := []struct {
+		name            string
+		tmpl            string
+		inputErrorFunc  func() error
+		expectedMessage string
+	}{
+		{
+			name: "case 0: built-in template",
+			tmpl: PrettyTemplate,
+			inputErrorFunc: func() error {
+				return Mask(Maskf(testMicroErr, "test annotation"))
+			},
+			expectedMessage: "Test kind: test annotation",
+		},
+		{
+			name: "case 1: custom template keeping the kind as is",
+			tmpl: `{{ .Title }}{{ with .Annotation }} ({{ . }}){{ end }}`,
+			inputErrorFunc: func() error {
+				return Mask(Maskf(testMicroErr, "test annotation"))
+			},
+			expectedMessage: "test kind (test annotation)",
+		},
+		{
+			name: "case 2: custom template extending the built-in template",
+			tmpl: `{{ define "pretty" }}` + PrettyTemplate + `{{ end }}error: {{ template "pretty" . }}{{ with .Desc }} - {{ . }}{{ end }}`,
+			inputErrorFunc: func() error {
+				return Mask(testMicroErr)
+			},
+			expectedMessage: "error: Test kind - test-desc",
+		},
+		{
+			name: "case 3: custom template with causes",
+			tmpl: `{{ .Kind }}{{ range .Causes }} <- {{ .Type }}{{ end }}`,
+			inputErrorFunc: func() error {
+				return Wrapf(testMicroErr, Mask(errors.New("test cause")), "test annotation")
+			},
+			expectedMessage: "testKind <- *errors.errorString",
+		},
+		{
+			name: "case 4: custom template with fields",
+			tmpl: `{{ .Kind }} request_id={{ .Fields.request_id }}`,
+			inputErrorFunc: func() error {
+				defer func(e []namedExtractor) { contextExtractors = e }(contextExtractors)
+				contextExtractors = nil
+				RegisterContextExtractor("request_id", ContextValue(testRequestIDKey{}))
+
+				ctx := context.WithValue(context.Background(), testRequestIDKey{}, "test-request-id")
+				return MaskCtx(ctx, testMicroErr)
+			},
+			expectedMessage: "testKind request_id=test-request-id",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			tmpl := template.Must(template.New("test").Funcs(TemplateFuncs()).Parse(tc.tmpl))
+
+			message, err := Render(tc.inputErrorFunc(), tmpl)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if message != tc.expectedMessage {
+				t.Fatalf("expected %q got %q", tc.expectedMessage, message)
+			}
+		})
+	}
+}
+
+func Test_Render_Error(t *testing.T) {
+	tmpl := template.Must(template.New("test").Parse(`{{ .Unknown }}`))
+
+	_, err := Render(testMicroErr, tmpl)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func Test_NewView(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inputErrorFunc func() error
+		expectedView   View
+	}{
+		{
+			name: "case 0: nil",
+			inputErrorFunc: func() error {
+				return nil
+			},
+			expectedView: View{},
+		},
+		{
+			name: "case 1: error=errors.New no masking",
+			inputErrorFunc: func() error {
+				return errors.New("test error")
+			},
+			expectedView: View{
+				Kind:  "unknown",
+				Title: "test error",
+			},
+		},
+		{
+			name: "case 2: error=microerror.Error with code depth=1 Maskf",
+			inputErrorFunc: func() error {
+				return Maskf(testCodeMicroErr, "test annotation")
+			},
+			expectedView: View{
+				Code:       "GS-1042",
+				Kind:       "invalidConfigError",
+				Title:      "invalid config error",
+				Annotated:  true,
+				Annotation: "test annotation",
+				Stack:      []StackEntry{{}},
+			},
+		},
+		{
+			name: "case 3: error=microerror.Error of unknown kind depth=1 Maskf",
+			inputErrorFunc: func() error {
+				return Maskf(&Error{Kind: kindUnknown}, "test annotation")
+			},
+			expectedView: View{
+				Kind:       "unknown",
+				Annotated:  true,
+				Annotation: "test annotation",
+				Stack:      []StackEntry{{}},
+			},
+		},
+		{
+			name: "case 4: error=microerror.Error Wrapf cause=errors.New",
+			inputErrorFunc: func() error {
+				return Wrapf(testMicroErr, errors.New("test cause"), "test annotation")
+			},
+			expectedView: View{
+				Kind:       "testKind",
+				Title:      "test kind",
+				Desc:       "test-desc",
+				Docs:       "test-docs",
+				Annotated:  true,
+				Annotation: "test annotation",
+				Stack:      []StackEntry{{}},
+				Causes: []Layer{
+					{
+						Message: "test cause",
+						Type:    "*errors.errorString",
+					},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			view := NewView(tc.inputErrorFunc())
+
+			// Stack entries are tested elsewhere.
+			opt := cmpopts.IgnoreFields(StackEntry{}, "File", "Line", "PC")
+			if !cmp.Equal(view, tc.expectedView, opt) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedView, view, opt))
+			}
+		})
+	}
+}
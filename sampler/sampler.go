@@ -0,0 +1,263 @@
+// Package sampler rate limits logging of identical errors. Errors are
+// identical when they share the same Fingerprint, i.e. the same kind masked
+// at the same sites. The first errors of a fingerprint are let through per
+// interval, the rest are suppressed and reported in a summary once the
+// interval ended. Summaries are passed periodically by Sampler.Run.
+package sampler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/giantswarm/microerror"
+)
+
+// Entry is passed to Config.Callback. It is either an error let through or
+// the summary of suppressed errors.
+type Entry struct {
+	// Err is the error let through or, for summaries, the last
+	// suppressed error.
+	Err         error
+	Fingerprint string
+	// Suppressed is the number of errors suppressed since Since. It is
+	// zero for errors let through.
+	Suppressed int
+	Since      time.Time
+}
+
+// Config configures a Sampler created with New.
+type Config struct {
+	// Burst is the number of errors of the same fingerprint let through
+	// per Interval.
+	Burst int
+	// Interval is the duration errors of the same fingerprint are counted
+	// for.
+	Interval time.Duration
+
+	// Callback receives errors let through and summaries. Either Callback
+	// or Logger must be set.
+	Callback func(ctx context.Context, entry Entry)
+	// Logger logs errors let through with their message and summaries
+	// with the message "suppressed errors". The level is derived from the
+	// severity of the error, see microerror.Severity. Either Callback or
+	// Logger must be set.
+	Logger *slog.Logger
+
+	// Now is optional and defaults to time.Now.
+	Now func() time.Time
+}
+
+type bucket struct {
+	start      time.Time
+	count      int
+	suppressed int
+	last       error
+}
+
+// Sampler rate limits logging of identical errors. It is safe for
+// concurrent use.
+type Sampler struct {
+	burst    int
+	interval time.Duration
+	callback func(ctx context.Context, entry Entry)
+	now      func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	// swept is when buckets whose interval ended were last dropped.
+	swept time.Time
+}
+
+func New(config Config) (*Sampler, error) {
+	if config.Burst < 1 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Burst must be positive", config)
+	}
+	if config.Interval <= 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Interval must be positive", config)
+	}
+	if (config.Callback == nil) == (config.Logger == nil) {
+		return nil, microerror.Maskf(invalidConfigError, "either %T.Callback or %T.Logger must be set", config, config)
+	}
+
+	s := &Sampler{
+		burst:    config.Burst,
+		interval: config.Interval,
+		callback: config.Callback,
+		now:      config.Now,
+
+		buckets: map[string]*bucket{},
+	}
+
+	if s.callback == nil {
+		s.callback = logEntry(config.Logger)
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+
+	return s, nil
+}
+
+// Log passes err to the callback unless Burst errors of the same
+// fingerprint were let through during the current interval already. In
+// that case err is suppressed. When the interval of the fingerprint ended
+// the summary of errors suppressed during it is passed first. Nil errors
+// are ignored.
+//
+// Once per Interval Log also drops the fingerprints of all intervals which
+// ended, like Flush does, so memory stays bounded without Run.
+func (s *Sampler) Log(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	fingerprint := Fingerprint(err)
+	now := s.now()
+
+	var entries []Entry
+	{
+		s.mu.Lock()
+
+		if !now.Before(s.swept.Add(s.interval)) {
+			entries = s.expire(entries, now)
+		}
+
+		b, ok := s.buckets[fingerprint]
+		if !ok {
+			b = &bucket{start: now}
+			s.buckets[fingerprint] = b
+		}
+		if !now.Before(b.start.Add(s.interval)) {
+			if b.suppressed > 0 {
+				entries = append(entries, b.summary(fingerprint))
+			}
+			*b = bucket{start: now}
+		}
+
+		if b.count < s.burst {
+			b.count++
+			entries = append(entries, Entry{Err: err, Fingerprint: fingerprint, Since: b.start})
+		} else {
+			b.suppressed++
+			b.last = err
+		}
+
+		s.mu.Unlock()
+	}
+
+	for _, e := range entries {
+		s.callback(ctx, e)
+	}
+}
+
+// Run calls Flush every Interval until ctx is done. Without Run or
+// scheduling Flush otherwise, summaries are only passed when another error
+// of the same fingerprint is logged after the interval ended.
+func (s *Sampler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Flush(ctx)
+		}
+	}
+}
+
+// Flush passes the summaries of all fingerprints whose interval ended to
+// the callback and forgets about them. It is called by Run and meant to be
+// called periodically otherwise, so suppressed errors are reported even
+// when no further errors of their fingerprint occur.
+func (s *Sampler) Flush(ctx context.Context) {
+	now := s.now()
+
+	var entries []Entry
+	{
+		s.mu.Lock()
+		entries = s.expire(entries, now)
+		s.mu.Unlock()
+	}
+
+	for _, e := range entries {
+		s.callback(ctx, e)
+	}
+}
+
+// expire drops the buckets whose interval ended at now and appends their
+// summaries to entries. s.mu must be held.
+func (s *Sampler) expire(entries []Entry, now time.Time) []Entry {
+	for fingerprint, b := range s.buckets {
+		if now.Before(b.start.Add(s.interval)) {
+			continue
+		}
+		if b.suppressed > 0 {
+			entries = append(entries, b.summary(fingerprint))
+		}
+		delete(s.buckets, fingerprint)
+	}
+	s.swept = now
+
+	return entries
+}
+
+func (b *bucket) summary(fingerprint string) Entry {
+	return Entry{
+		Err:         b.last,
+		Fingerprint: fingerprint,
+		Suppressed:  b.suppressed,
+		Since:       b.start,
+	}
+}
+
+// Fingerprint identifies err by its kind and the sites it was masked at.
+// Errors never masked are identified by their kind, Go type and message.
+func Fingerprint(err error) string {
+	h := fnv.New64a()
+
+	kind := "unknown"
+	var eErr *microerror.Error
+	if errors.As(err, &eErr) {
+		kind = eErr.Kind
+	}
+	_, _ = h.Write([]byte(kind))
+
+	stack := microerror.Stack(err)
+	for _, e := range stack {
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(e.File + ":" + strconv.Itoa(e.Line)))
+	}
+	if len(stack) == 0 {
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(fmt.Sprintf("%T", err)))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(err.Error()))
+	}
+
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+func logEntry(logger *slog.Logger) func(ctx context.Context, entry Entry) {
+	return func(ctx context.Context, entry Entry) {
+		level := microerror.Severity(entry.Err).SlogLevel()
+		if entry.Suppressed == 0 {
+			logger.Log(ctx, level, entry.Err.Error(), "fingerprint", entry.Fingerprint)
+			return
+		}
+
+		logger.Log(ctx, level, "suppressed errors",
+			"error", entry.Err.Error(),
+			"fingerprint", entry.Fingerprint,
+			"suppressed", entry.Suppressed,
+			"since", entry.Since,
+		)
+	}
+}
@@ -0,0 +1,439 @@
+package sampler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/microerror"
+)
+
+var testMicroErr = &microerror.Error{
+	Kind: "testKind",
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func Test_Sampler(t *testing.T) {
+	errA := func(i int) error {
+		return microerror.Maskf(testMicroErr, "a%d", i)
+	}
+	errB := func(i int) error {
+		return microerror.Maskf(testMicroErr, "b%d", i)
+	}
+
+	// step is either an error to log, a flush when err is nil or just the
+	// advancement of the clock.
+	type step struct {
+		advance time.Duration
+		err     error
+		flush   bool
+	}
+
+	testCases := []struct {
+		name            string
+		steps           []step
+		expectedEntries []string
+	}{
+		{
+			name: "case 0: burst let through, rest suppressed",
+			steps: []step{
+				{err: errA(1)},
+				{err: errA(2)},
+				{err: errA(3)},
+				{err: errA(4)},
+				{flush: true},
+			},
+			expectedEntries: []string{
+				"0s test kind: a1",
+				"0s test kind: a2",
+			},
+		},
+		{
+			name: "case 1: summary emitted with next error after interval",
+			steps: []step{
+				{err: errA(1)},
+				{err: errA(2)},
+				{err: errA(3)},
+				{advance: time.Minute, err: errA(4)},
+				{err: errA(5)},
+				{advance: time.Minute, err: errA(6)},
+			},
+			expectedEntries: []string{
+				"0s test kind: a1",
+				"0s test kind: a2",
+				"0s test kind: a3 (suppressed 1)",
+				"1m0s test kind: a4",
+				"1m0s test kind: a5",
+				"2m0s test kind: a6",
+			},
+		},
+		{
+			name: "case 2: summary emitted by flush after interval",
+			steps: []step{
+				{err: errA(1)},
+				{err: errA(2)},
+				{err: errA(3)},
+				{err: errA(4)},
+				{advance: 59 * time.Second, flush: true},
+				{advance: time.Second, flush: true},
+				{flush: true},
+			},
+			expectedEntries: []string{
+				"0s test kind: a1",
+				"0s test kind: a2",
+				"0s test kind: a4 (suppressed 2)",
+			},
+		},
+		{
+			name: "case 3: fingerprints are sampled independently",
+			steps: []step{
+				{err: errA(1)},
+				{err: errA(2)},
+				{err: errA(3)},
+				{err: errB(1)},
+				{err: errB(2)},
+				{err: errB(3)},
+			},
+			expectedEntries: []string{
+				"0s test kind: a1",
+				"0s test kind: a2",
+				"0s test kind: b1",
+				"0s test kind: b2",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			clock := &testClock{now: start}
+
+			var entries []string
+			s, err := New(Config{
+				Burst:    2,
+				Interval: time.Minute,
+				Callback: func(ctx context.Context, entry Entry) {
+					e := fmt.Sprintf("%s %s", entry.Since.Sub(start), entry.Err)
+					if entry.Suppressed > 0 {
+						e += fmt.Sprintf(" (suppressed %d)", entry.Suppressed)
+					}
+					entries = append(entries, e)
+				},
+				Now: clock.Now,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, st := range tc.steps {
+				clock.now = clock.now.Add(st.advance)
+				if st.err != nil {
+					s.Log(context.Background(), st.err)
+				}
+				if st.flush {
+					s.Flush(context.Background())
+				}
+			}
+
+			if !cmp.Equal(entries, tc.expectedEntries) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedEntries, entries))
+			}
+		})
+	}
+}
+
+func Test_Sampler_Logger(t *testing.T) {
+	clock := &testClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	b := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(b, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "fingerprint" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	s, err := New(Config{
+		Burst:    1,
+		Interval: time.Minute,
+		Logger:   logger,
+		Now:      clock.Now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		s.Log(context.Background(), microerror.MaskWithSeverity(microerror.Maskf(testMicroErr, "test annotation"), microerror.LevelWarning))
+	}
+	clock.now = clock.now.Add(time.Minute)
+	s.Flush(context.Background())
+
+	expected := strings.Join([]string{
+		`level=WARN msg="test kind: test annotation"`,
+		`level=WARN msg="suppressed errors" error="test kind: test annotation" suppressed=2 since=2024-01-01T00:00:00.000Z`,
+		``,
+	}, "\n")
+	if b.String() != expected {
+		t.Fatalf("\n\n%s\n", cmp.Diff(expected, b.String()))
+	}
+}
+
+func Test_Sampler_Concurrent(t *testing.T) {
+	var mu sync.Mutex
+	var passed, suppressed int
+
+	s, err := New(Config{
+		Burst:    5,
+		Interval: time.Hour,
+		Callback: func(ctx context.Context, entry Entry) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if entry.Suppressed > 0 {
+				suppressed += entry.Suppressed
+			} else {
+				passed++
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = microerror.Mask(testMicroErr)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Log(context.Background(), err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if passed != 5 {
+		t.Fatalf("expected 5 errors let through got %d", passed)
+	}
+	if suppressed != 0 {
+		t.Fatalf("expected no summary before the interval ended got %d suppressed", suppressed)
+	}
+}
+
+func Test_Fingerprint(t *testing.T) {
+	newErr := func() error {
+		return microerror.Mask(testMicroErr)
+	}
+
+	a, b := Fingerprint(newErr()), Fingerprint(newErr())
+	if a != b {
+		t.Fatalf("expected errors masked at the same site to share fingerprint got %q and %q", a, b)
+	}
+
+	c := Fingerprint(microerror.Mask(testMicroErr))
+	if a == c {
+		t.Fatalf("expected errors masked at different sites to differ")
+	}
+
+	d := Fingerprint(microerror.Mask(errors.New("test error")))
+	e := Fingerprint(microerror.Mask(errors.New("test error")))
+	if d == e {
+		t.Fatalf("expected errors masked at different sites to differ")
+	}
+
+	// Errors never masked are identified by their message and type.
+	if Fingerprint(errors.New("db down")) == Fingerprint(errors.New("disk full")) {
+		t.Fatalf("expected unmasked errors with different messages to differ")
+	}
+	if Fingerprint(errors.New("db down")) != Fingerprint(errors.New("db down")) {
+		t.Fatalf("expected unmasked errors with the same message to share fingerprint")
+	}
+	if Fingerprint(errors.New("db down")) == Fingerprint(fmt.Errorf("%w", errors.New("db down"))) {
+		t.Fatalf("expected unmasked errors of different types to differ")
+	}
+}
+
+func Test_Sampler_Run(t *testing.T) {
+	clock := &testClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	clockMu := sync.Mutex{}
+
+	summaries := make(chan Entry, 1)
+	s, err := New(Config{
+		Burst:    1,
+		Interval: time.Millisecond,
+		Callback: func(ctx context.Context, entry Entry) {
+			if entry.Suppressed > 0 {
+				summaries <- entry
+			}
+		},
+		Now: func() time.Time {
+			clockMu.Lock()
+			defer clockMu.Unlock()
+
+			return clock.Now()
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = microerror.Mask(testMicroErr)
+	for i := 0; i < 3; i++ {
+		s.Log(context.Background(), err)
+	}
+
+	clockMu.Lock()
+	clock.now = clock.now.Add(time.Millisecond)
+	clockMu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx)
+	}()
+
+	select {
+	case entry := <-summaries:
+		if entry.Suppressed != 2 {
+			t.Fatalf("expected 2 suppressed errors got %d", entry.Suppressed)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("expected summary to be passed by Run")
+	}
+
+	cancel()
+	<-done
+}
+
+func Test_Sampler_Log_Expire(t *testing.T) {
+	clock := &testClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	var suppressed int
+	s, err := New(Config{
+		Burst:    1,
+		Interval: time.Minute,
+		Callback: func(ctx context.Context, entry Entry) {
+			suppressed += entry.Suppressed
+		},
+		Now: clock.Now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unmasked errors with distinct messages have distinct fingerprints.
+	for i := 0; i < 100; i++ {
+		err := fmt.Errorf("test error %d", i)
+		s.Log(context.Background(), err)
+		s.Log(context.Background(), err)
+	}
+	if len(s.buckets) != 100 {
+		t.Fatalf("expected 100 buckets got %d", len(s.buckets))
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	s.Log(context.Background(), errors.New("test error"))
+
+	if len(s.buckets) != 1 {
+		t.Fatalf("expected expired buckets to be dropped, got %d buckets", len(s.buckets))
+	}
+	if suppressed != 100 {
+		t.Fatalf("expected 100 suppressed errors to be summarized got %d", suppressed)
+	}
+}
+
+func Test_New(t *testing.T) {
+	callback := func(ctx context.Context, entry Entry) {}
+
+	testCases := []struct {
+		name         string
+		config       Config
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "case 0: valid config",
+			config: Config{
+				Burst:    1,
+				Interval: time.Second,
+				Callback: callback,
+			},
+		},
+		{
+			name: "case 1: zero burst",
+			config: Config{
+				Interval: time.Second,
+				Callback: callback,
+			},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name: "case 2: zero interval",
+			config: Config{
+				Burst:    1,
+				Callback: callback,
+			},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name: "case 3: neither callback nor logger",
+			config: Config{
+				Burst:    1,
+				Interval: time.Second,
+			},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name: "case 4: both callback and logger",
+			config: Config{
+				Burst:    1,
+				Interval: time.Second,
+				Callback: callback,
+				Logger:   slog.Default(),
+			},
+			errorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			_, err := New(tc.config)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package microerror
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed schema.json
+var schema []byte
+
+var (
+	parsedSchemaOnce sync.Once
+	parsedSchema     map[string]interface{}
+)
+
+// Schema returns the JSON Schema describing documents rendered by JSON in
+// all formats. Changes to the rendered documents are always reflected in
+// the schema.
+func Schema() []byte {
+	return append([]byte(nil), schema...)
+}
+
+// Validate validates data against the JSON Schema returned by Schema. Only
+// the subset of JSON Schema used by it is supported.
+func Validate(data []byte) error {
+	parsedSchemaOnce.Do(func() {
+		err := json.Unmarshal(schema, &parsedSchema)
+		if err != nil {
+			panic(err.Error())
+		}
+	})
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var v interface{}
+	err := d.Decode(&v)
+	if err != nil {
+		return Maskf(validationFailedError, "%s", err)
+	}
+	if d.More() {
+		return Maskf(validationFailedError, "unexpected data after document")
+	}
+
+	return validate(parsedSchema, parsedSchema, v, "")
+}
+
+// validate validates v against s. root is used to resolve references and
+// path is the JSON Pointer of v used in error annotations.
+func validate(root map[string]interface{}, s map[string]interface{}, v interface{}, path string) error {
+	if ref, ok := s["$ref"].(string); ok {
+		r, ok := resolveReference(root, ref)
+		if !ok {
+			return Maskf(validationFailedError, "%s: unknown reference %#q", pointer(path), ref)
+		}
+		err := validate(root, r, v, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	if t, ok := s["type"].(string); ok && !hasType(v, t) {
+		return Maskf(validationFailedError, "%s: expected %s", pointer(path), t)
+	}
+
+	if enum, ok := s["enum"].([]interface{}); ok && !inEnum(v, enum) {
+		return Maskf(validationFailedError, "%s: value not allowed", pointer(path))
+	}
+
+	if pattern, ok := s["pattern"].(string); ok {
+		str, ok := v.(string)
+		if ok {
+			r, err := regexp.Compile(pattern)
+			if err != nil {
+				return Maskf(validationFailedError, "%s: invalid pattern %#q", pointer(path), pattern)
+			}
+			if !r.MatchString(str) {
+				return Maskf(validationFailedError, "%s: value does not match %#q", pointer(path), pattern)
+			}
+		}
+	}
+
+	if anyOf, ok := s["anyOf"].([]interface{}); ok && !matchesAny(root, anyOf, v, path) {
+		return Maskf(validationFailedError, "%s: value not allowed", pointer(path))
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return validateObject(root, s, v, path)
+	case []interface{}:
+		items, ok := s["items"].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for i, e := range v {
+			err := validate(root, items, e, path+"/"+strconv.Itoa(i))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func matchesAny(root map[string]interface{}, schemas []interface{}, v interface{}, path string) bool {
+	for _, a := range schemas {
+		s, _ := a.(map[string]interface{})
+		if validate(root, s, v, path) == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateObject(root map[string]interface{}, s map[string]interface{}, v map[string]interface{}, path string) error {
+	required, _ := s["required"].([]interface{})
+	for _, r := range required {
+		name, _ := r.(string)
+		if _, ok := v[name]; !ok {
+			return Maskf(validationFailedError, "%s: missing property %#q", pointer(path), name)
+		}
+	}
+
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	properties, _ := s["properties"].(map[string]interface{})
+	for _, k := range keys {
+		p := path + "/" + strings.ReplaceAll(strings.ReplaceAll(k, "~", "~0"), "/", "~1")
+
+		ps, ok := properties[k].(map[string]interface{})
+		if ok {
+			err := validate(root, ps, v[k], p)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch a := s["additionalProperties"].(type) {
+		case bool:
+			if !a {
+				return Maskf(validationFailedError, "%s: unexpected property", pointer(p))
+			}
+		case map[string]interface{}:
+			err := validate(root, a, v[k], p)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveReference resolves local references like "#/$defs/error".
+func resolveReference(root map[string]interface{}, ref string) (map[string]interface{}, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false
+	}
+
+	s := root
+	for _, p := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+		next, ok := s[p].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		s = next
+	}
+
+	return s, true
+}
+
+func hasType(v interface{}, t string) bool {
+	switch v := v.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case string:
+		return t == "string"
+	case json.Number:
+		if t == "number" {
+			return true
+		}
+		_, err := v.Int64()
+		return t == "integer" && err == nil
+	case []interface{}:
+		return t == "array"
+	case map[string]interface{}:
+		return t == "object"
+	default:
+		return false
+	}
+}
+
+func inEnum(v interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		switch v := v.(type) {
+		case json.Number:
+			f, err := v.Float64()
+			if err == nil && f == e {
+				return true
+			}
+		default:
+			if v == e {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func pointer(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,160 @@
+package microerror
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// Test_Schema_Golden ensures every golden file rendered by JSON matches the
+// published schema.
+func Test_Schema_Golden(t *testing.T) {
+	var goldens []string
+	for _, p := range []string{"case-*.golden", "v2-*.golden"} {
+		matches, err := filepath.Glob(filepath.Join("testdata", p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		goldens = append(goldens, matches...)
+	}
+	if len(goldens) == 0 {
+		t.Fatal("expected golden files")
+	}
+
+	for _, golden := range goldens {
+		data, err := os.ReadFile(golden) // nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = Validate(data)
+		if err != nil {
+			t.Errorf("%s: %s", golden, err)
+		}
+	}
+}
+
+func Test_Schema_Validate(t *testing.T) {
+	defer func(e []namedExtractor) { contextExtractors = e }(contextExtractors)
+	contextExtractors = nil
+
+	RegisterContextExtractor("traceparent", Traceparent)
+
+	testCases := []struct {
+		name          string
+		input         func() string
+		errorMatcher  func(error) bool
+		expectedError string
+	}{
+		{
+			name: "case 0: V1 with code, severity, categories and fields",
+			input: func() string {
+				e := &Error{
+					Code:       "GS-1042",
+					Kind:       "testKind",
+					Severity:   LevelWarning,
+					Categories: NewCategories("test"),
+				}
+				ctx := WithTraceparent(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+				return JSON(MaskCtx(ctx, Wrapf(e, Mask(errors.New("test error")), "test annotation")))
+			},
+		},
+		{
+			name: "case 1: V2 with fmt.Errorf layer",
+			input: func() string {
+				SetJSONFormat(JSONFormatV2)
+				defer SetJSONFormat(JSONFormatV1)
+
+				return JSON(Mask(errors.Join(Maskf(testMicroErr, "test annotation"))))
+			},
+		},
+		{
+			name: "case 2: invalid JSON",
+			input: func() string {
+				return `{"kind":`
+			},
+			errorMatcher:  IsValidationFailed,
+			expectedError: "validation failed error: unexpected EOF",
+		},
+		{
+			name: "case 3: missing schema_version",
+			input: func() string {
+				return `{"kind":"testKind"}`
+			},
+			errorMatcher:  IsValidationFailed,
+			expectedError: "validation failed error: /: missing property `schema_version`",
+		},
+		{
+			name: "case 4: unknown property",
+			input: func() string {
+				return `{"kind":"testKind","schema_version":1,"foo":"bar"}`
+			},
+			errorMatcher:  IsValidationFailed,
+			expectedError: "validation failed error: /foo: unexpected property",
+		},
+		{
+			name: "case 5: unknown schema_version",
+			input: func() string {
+				return `{"kind":"testKind","schema_version":3}`
+			},
+			errorMatcher:  IsValidationFailed,
+			expectedError: "validation failed error: /schema_version: value not allowed",
+		},
+		{
+			name: "case 6: invalid nested stack entry",
+			input: func() string {
+				return `{"kind":"testKind","schema_version":1,"cause":{"kind":"unknown","stack":[{"file":"a.go","line":"1"}]}}`
+			},
+			errorMatcher:  IsValidationFailed,
+			expectedError: "validation failed error: /cause/stack/0/line: expected integer",
+		},
+		{
+			name: "case 7: unknown severity name",
+			input: func() string {
+				return `{"kind":"testKind","schema_version":1,"severity":"fatal"}`
+			},
+			errorMatcher:  IsValidationFailed,
+			expectedError: "validation failed error: /severity: value not allowed",
+		},
+		{
+			name: "case 8: unknown severity level",
+			input: func() string {
+				return `{"kind":"testKind","schema_version":1,"severity":"Level(9)"}`
+			},
+		},
+		{
+			name: "case 9: non string field",
+			input: func() string {
+				return `{"kind":"testKind","schema_version":1,"fields":{"a/b":1}}`
+			},
+			errorMatcher:  IsValidationFailed,
+			expectedError: "validation failed error: /fields/a~1b: expected string",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := Validate([]byte(tc.input()))
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if err != nil && err.Error() != tc.expectedError {
+				t.Fatalf("error == %q, want %q", err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// Package sentry converts error chains into Sentry event payloads without
+// depending on the Sentry SDK. The resulting JSON documents can be sent to
+// the Sentry store endpoint or merged into events built by the SDK.
+package sentry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+)
+
+// Event is the subset of the Sentry event payload describing an error. See
+// https://develop.sentry.dev/sdk/event-payloads/.
+type Event struct {
+	Level     string            `json:"level"`
+	Platform  string            `json:"platform"`
+	Exception Exceptions        `json:"exception"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Extra     map[string]string `json:"extra,omitempty"`
+}
+
+// Exceptions holds one Exception per layer of the error chain, innermost
+// first as expected by Sentry.
+type Exceptions struct {
+	Values []Exception `json:"values"`
+}
+
+type Exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+// Stacktrace holds the frames of an Exception, oldest call first as
+// expected by Sentry.
+type Stacktrace struct {
+	Frames []Frame `json:"frames"`
+}
+
+type Frame struct {
+	Function string `json:"function,omitempty"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+// NewEvent converts err into an Event. Every layer of the error chain, as
+// returned by microerror.Layers, becomes an Exception. Exceptions of
+// microerror errors have their kind as type. Others have their Go type.
+//
+// Frames of packages matching one of inAppPrefixes are marked as in app.
+// Without prefixes all frames outside of the standard library are in app.
+func NewEvent(err error, inAppPrefixes ...string) *Event {
+	e := &Event{
+		Level:    level(microerror.Severity(err)),
+		Platform: "go",
+	}
+
+	layers := microerror.Layers(err)
+	for i := len(layers) - 1; i >= 0; i-- {
+		e.Exception.Values = append(e.Exception.Values, newException(layers[i], inAppPrefixes))
+	}
+
+	var eerr *microerror.Error
+	if errors.As(err, &eerr) {
+		e.Tags = map[string]string{
+			"kind": eerr.Kind,
+		}
+		if eerr.Code != "" {
+			e.Tags["code"] = eerr.Code
+		}
+	}
+
+	for k, v := range microerror.Fields(err) {
+		e.setExtra(k, v)
+	}
+	for _, l := range layers {
+		if l.Error == nil {
+			continue
+		}
+		e.setExtra("desc", l.Error.Desc)
+		e.setExtra("docs", l.Error.Docs)
+		e.setExtra("annotation", l.Annotation)
+		break
+	}
+
+	return e
+}
+
+// JSON renders err as Sentry event JSON document. See NewEvent.
+func JSON(err error, inAppPrefixes ...string) ([]byte, error) {
+	bytes, jsonErr := json.Marshal(NewEvent(err, inAppPrefixes...))
+	if jsonErr != nil {
+		return nil, fmt.Errorf("sentry.JSON: %w", jsonErr)
+	}
+
+	return bytes, nil
+}
+
+func (e *Event) setExtra(key string, value string) {
+	if value == "" {
+		return
+	}
+	if e.Extra == nil {
+		e.Extra = map[string]string{}
+	}
+	e.Extra[key] = value
+}
+
+func newException(l microerror.Layer, inAppPrefixes []string) Exception {
+	x := Exception{
+		Type:  l.Type,
+		Value: l.Message,
+	}
+	if l.Error != nil {
+		x.Type = l.Error.Kind
+	}
+
+	if len(l.Stack) == 0 {
+		return x
+	}
+
+	x.Stacktrace = &Stacktrace{}
+	for i := len(l.Stack) - 1; i >= 0; i-- {
+		x.Stacktrace.Frames = append(x.Stacktrace.Frames, newFrame(l.Stack[i], inAppPrefixes))
+	}
+
+	return x
+}
+
+func newFrame(s microerror.StackEntry, inAppPrefixes []string) Frame {
+	f := Frame{
+		Filename: filepath.Base(s.File),
+		AbsPath:  s.File,
+		Lineno:   s.Line,
+	}
+
+	fn := runtime.FuncForPC(s.PC)
+	if fn != nil {
+		f.Module, f.Function = splitFunctionName(fn.Name())
+	}
+
+	if len(inAppPrefixes) == 0 {
+		f.InApp = f.Module != "" && !isStandardLibrary(f.Module)
+	}
+	for _, p := range inAppPrefixes {
+		if strings.HasPrefix(f.Module, p) {
+			f.InApp = true
+			break
+		}
+	}
+
+	return f
+}
+
+// splitFunctionName splits e.g.
+// "github.com/giantswarm/microerror/sentry.(*T).Method" into the package
+// path "github.com/giantswarm/microerror/sentry" and the function
+// "(*T).Method".
+func splitFunctionName(name string) (string, string) {
+	var dir string
+	i := strings.LastIndex(name, "/")
+	if i >= 0 {
+		dir, name = name[:i+1], name[i+1:]
+	}
+
+	pkg, fn, ok := strings.Cut(name, ".")
+	if !ok {
+		return "", dir + name
+	}
+
+	return dir + pkg, fn
+}
+
+// isStandardLibrary reports whether module is a standard library package.
+// Only their first path element contains no dot.
+func isStandardLibrary(module string) bool {
+	first, _, _ := strings.Cut(module, "/")
+	return !strings.Contains(first, ".")
+}
+
+func level(l microerror.Level) string {
+	switch l {
+	case microerror.LevelDebug:
+		return "debug"
+	case microerror.LevelInfo:
+		return "info"
+	case microerror.LevelWarning:
+		return "warning"
+	case microerror.LevelCritical:
+		return "fatal"
+	default:
+		return "error"
+	}
+}
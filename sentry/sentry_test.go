@@ -0,0 +1,180 @@
+package sentry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/microerror"
+)
+
+var update = flag.Bool("update", false, "update resource.golden file")
+
+var testMicroErr = &microerror.Error{
+	Desc:     "test-desc",
+	Docs:     "test-docs",
+	Kind:     "testKind",
+	Severity: microerror.LevelWarning,
+}
+
+type testRequestIDKey struct{}
+
+func init() {
+	microerror.RegisterContextExtractor("test_request_id", microerror.ContextValue(testRequestIDKey{}))
+}
+
+// Test_JSON tests rendering errors as Sentry events.
+//
+// It uses golden file as reference and when changes to template are
+// intentional, they can be updated by providing -update flag for go test.
+//
+//	go test ./sentry -run Test_JSON -update
+func Test_JSON(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inputErrorFunc func() error
+		inAppPrefixes  []string
+		expectedGolden string
+	}{
+		{
+			name: "case 0: error=microerror.Error no masking",
+			inputErrorFunc: func() error {
+				return testMicroErr
+			},
+			expectedGolden: "case-0.golden",
+		},
+		{
+			name: "case 1: error=errors.New depth=1 Mask",
+			inputErrorFunc: func() error {
+				return microerror.Mask(errors.New("test error"))
+			},
+			expectedGolden: "case-1.golden",
+		},
+		{
+			name: "case 2: error=microerror.Error depth=3 Maskf",
+			inputErrorFunc: func() error {
+				err := microerror.Maskf(testMicroErr, "test annotation")
+				err = microerror.Mask(err)
+				err = microerror.Mask(err)
+				return err
+			},
+			expectedGolden: "case-2.golden",
+		},
+		{
+			name: "case 3: error=microerror.Error wrapped with fmt.Errorf and context fields",
+			inputErrorFunc: func() error {
+				err := microerror.Maskf(testMicroErr, "test annotation")
+				err = microerror.Mask(err)
+				ctx := context.WithValue(context.Background(), testRequestIDKey{}, "req-1")
+				return microerror.MaskCtx(ctx, fmt.Errorf("test wrapper: %w", err))
+			},
+			expectedGolden: "case-3.golden",
+		},
+		{
+			name: "case 4: in app prefixes",
+			inputErrorFunc: func() error {
+				return microerror.Mask(errors.New("test error"))
+			},
+			inAppPrefixes:  []string{"github.com/giantswarm/other"},
+			expectedGolden: "case-4.golden",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			b, err := JSON(tc.inputErrorFunc(), tc.inAppPrefixes...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var actual string
+			{
+				buf := &bytes.Buffer{}
+				err := json.Indent(buf, b, "", "\t")
+				if err != nil {
+					t.Fatal(err)
+				}
+				actual = buf.String() + "\n"
+			}
+			// Change paths to avoid prefixes like
+			// "/Users/username/go/src/" so this can test can be
+			// executed on different machines.
+			{
+				r := regexp.MustCompile(`("abs_path"\s*:\s*")\S+(/[^/"]+.go")`)
+				actual = r.ReplaceAllString(actual, "$1--REPLACED--$2")
+			}
+
+			var expected string
+			{
+				golden := filepath.Join("testdata", tc.expectedGolden)
+				if *update {
+					err := os.WriteFile(golden, []byte(actual), 0644) //nolint:gosec
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				bytes, err := os.ReadFile(golden) // nolint:gosec
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expected = string(bytes)
+			}
+
+			if actual != expected {
+				t.Fatalf("\n\n%s\n", cmp.Diff(actual, expected))
+			}
+		})
+	}
+}
+
+func Test_splitFunctionName(t *testing.T) {
+	testCases := []struct {
+		name             string
+		expectedModule   string
+		expectedFunction string
+	}{
+		{
+			name:             "github.com/giantswarm/microerror/sentry.(*T).Method",
+			expectedModule:   "github.com/giantswarm/microerror/sentry",
+			expectedFunction: "(*T).Method",
+		},
+		{
+			name:             "github.com/giantswarm/microerror.Mask",
+			expectedModule:   "github.com/giantswarm/microerror",
+			expectedFunction: "Mask",
+		},
+		{
+			name:             "main.main.func1",
+			expectedModule:   "main",
+			expectedFunction: "main.func1",
+		},
+		{
+			name:             "runtime.goexit",
+			expectedModule:   "runtime",
+			expectedFunction: "goexit",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			module, function := splitFunctionName(tc.name)
+			if module != tc.expectedModule || function != tc.expectedFunction {
+				t.Fatalf("got %#q %#q, want %#q %#q", module, function, tc.expectedModule, tc.expectedFunction)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package microerror
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// Level is the severity of an error. The zero value means the severity is
+// not set.
+type Level int
+
+const (
+	LevelDebug Level = iota + 1
+	LevelInfo
+	LevelWarning
+	LevelError
+	LevelCritical
+)
+
+var levelNames = map[Level]string{
+	LevelDebug:    "debug",
+	LevelInfo:     "info",
+	LevelWarning:  "warning",
+	LevelError:    "error",
+	LevelCritical: "critical",
+}
+
+func (l Level) String() string {
+	name, ok := levelNames[l]
+	if !ok {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+	return name
+}
+
+// MarshalText marshals l as its name. Unknown levels never fail, so
+// rendering errors with them doesn't either. They are marshaled as
+// "Level(N)" like String does.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *Level) UnmarshalText(text []byte) error {
+	for level, name := range levelNames {
+		if name == string(text) {
+			*l = level
+			return nil
+		}
+	}
+
+	var n int
+	_, err := fmt.Sscanf(string(text), "Level(%d)", &n)
+	if err == nil && fmt.Sprintf("Level(%d)", n) == string(text) {
+		*l = Level(n)
+		return nil
+	}
+
+	return fmt.Errorf("microerror.Level.UnmarshalText: unknown level %#q", text)
+}
+
+// SlogLevel maps l to the corresponding slog.Level. LevelCritical, which has
+// no equivalent, is mapped four steps above slog.LevelError following the
+// spacing of the predefined slog levels.
+func (l Level) SlogLevel() slog.Level {
+	switch l {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelWarning:
+		return slog.LevelWarn
+	case LevelCritical:
+		return slog.LevelError + 4
+	default:
+		return slog.LevelError
+	}
+}
+
+// Severity resolves the severity of err. A severity set with
+// MaskWithSeverity takes precedence, the outermost one winning as it is
+// closest to the code handling err. Otherwise the Severity of the Error in
+// the chain is used. Errors without any severity are LevelError. Severity
+// of nil is the zero Level.
+func Severity(err error) Level {
+	if err == nil {
+		return 0
+	}
+
+	l := maskedSeverity(err)
+	if l != 0 {
+		return l
+	}
+
+	var eerr *Error
+	if errors.As(err, &eerr) && eerr.Severity != 0 {
+		return eerr.Severity
+	}
+
+	return LevelError
+}
+
+// MaskWithSeverity is like Mask but also overrides the severity of err. This
+// allows e.g. a reconciler to log an otherwise critical error at debug level
+// when it is expected to resolve itself.
+func MaskWithSeverity(err error, l Level) error {
+	if err == nil {
+		return nil
+	}
+
+	serr := mask(err)
+	serr.severity = l
+
+	return serr
+}
+
+// maskedSeverity returns the outermost severity set with MaskWithSeverity.
+func maskedSeverity(err error) Level {
+	var l Level
+	walk(err, func(e error) bool {
+		serr, ok := e.(*stackedError)
+		if ok {
+			l = serr.severity
+		}
+		return l == 0
+	})
+
+	return l
+}
@@ -0,0 +1,224 @@
+package microerror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_Severity(t *testing.T) {
+	testSeverityMicroErr := &Error{
+		Kind:     "testSeverityKind",
+		Severity: LevelCritical,
+	}
+
+	testCases := []struct {
+		name             string
+		inputErrorFunc   func() error
+		expectedSeverity Level
+	}{
+		{
+			name: "case 0: nil",
+			inputErrorFunc: func() error {
+				return nil
+			},
+			expectedSeverity: 0,
+		},
+		{
+			name: "case 1: error=errors.New defaults to error",
+			inputErrorFunc: func() error {
+				return Mask(errors.New("test error"))
+			},
+			expectedSeverity: LevelError,
+		},
+		{
+			name: "case 2: error=microerror.Error without severity defaults to error",
+			inputErrorFunc: func() error {
+				return Maskf(testMicroErr, "test annotation")
+			},
+			expectedSeverity: LevelError,
+		},
+		{
+			name: "case 3: error=microerror.Error with severity depth=3",
+			inputErrorFunc: func() error {
+				err := Maskf(testSeverityMicroErr, "test annotation")
+				err = Mask(err)
+				err = Mask(err)
+				return err
+			},
+			expectedSeverity: LevelCritical,
+		},
+		{
+			name: "case 4: override at Mask time",
+			inputErrorFunc: func() error {
+				err := Maskf(testSeverityMicroErr, "test annotation")
+				err = MaskWithSeverity(err, LevelDebug)
+				err = Mask(err)
+				return err
+			},
+			expectedSeverity: LevelDebug,
+		},
+		{
+			name: "case 5: outermost override wins",
+			inputErrorFunc: func() error {
+				err := MaskWithSeverity(testSeverityMicroErr, LevelDebug)
+				err = MaskWithSeverity(err, LevelWarning)
+				err = Mask(err)
+				return err
+			},
+			expectedSeverity: LevelWarning,
+		},
+		{
+			name: "case 6: override through fmt.Errorf wrapping",
+			inputErrorFunc: func() error {
+				err := MaskWithSeverity(testSeverityMicroErr, LevelInfo)
+				err = fmt.Errorf("wrapped: %w", err)
+				return Mask(err)
+			},
+			expectedSeverity: LevelInfo,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			severity := Severity(tc.inputErrorFunc())
+			if severity != tc.expectedSeverity {
+				t.Fatalf("severity = %v, want %v", severity, tc.expectedSeverity)
+			}
+		})
+	}
+}
+
+func Test_Severity_JSON(t *testing.T) {
+	testSeverityMicroErr := &Error{
+		Kind:     "testSeverityKind",
+		Severity: LevelCritical,
+	}
+
+	var o JSONError
+	err := json.Unmarshal([]byte(JSON(Mask(testSeverityMicroErr))), &o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Severity != LevelCritical {
+		t.Fatalf("severity = %v, want %v", o.Severity, LevelCritical)
+	}
+
+	err = json.Unmarshal([]byte(JSON(MaskWithSeverity(testSeverityMicroErr, LevelDebug))), &o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Severity != LevelDebug {
+		t.Fatalf("severity = %v, want %v", o.Severity, LevelDebug)
+	}
+	if testSeverityMicroErr.Severity != LevelCritical {
+		t.Fatalf("override must not modify the original error")
+	}
+}
+
+func Test_Level_SlogLevel(t *testing.T) {
+	testCases := []struct {
+		level    Level
+		expected slog.Level
+	}{
+		{level: LevelDebug, expected: slog.LevelDebug},
+		{level: LevelInfo, expected: slog.LevelInfo},
+		{level: LevelWarning, expected: slog.LevelWarn},
+		{level: LevelError, expected: slog.LevelError},
+		{level: LevelCritical, expected: slog.LevelError + 4},
+		{level: 0, expected: slog.LevelError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.level.String(), func(t *testing.T) {
+			if tc.level.SlogLevel() != tc.expected {
+				t.Fatalf("slog level = %v, want %v", tc.level.SlogLevel(), tc.expected)
+			}
+		})
+	}
+}
+
+func Test_Level_Text(t *testing.T) {
+	testCases := []struct {
+		name         string
+		level        Level
+		expectedText string
+	}{
+		{
+			name:         "case 0: known level",
+			level:        LevelWarning,
+			expectedText: "warning",
+		},
+		{
+			name:         "case 1: unknown level",
+			level:        Level(9),
+			expectedText: "Level(9)",
+		},
+		{
+			name:         "case 2: negative unknown level",
+			level:        Level(-1),
+			expectedText: "Level(-1)",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			text, err := tc.level.MarshalText()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(text) != tc.expectedText {
+				t.Fatalf("text = %q, want %q", text, tc.expectedText)
+			}
+
+			var l Level
+			err = l.UnmarshalText(text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if l != tc.level {
+				t.Fatalf("level = %v, want %v", l, tc.level)
+			}
+		})
+	}
+
+	var l Level
+	for _, text := range []string{"fatal", "Level(x)", "Level(1) "} {
+		if l.UnmarshalText([]byte(text)) == nil {
+			t.Fatalf("expected error for %q", text)
+		}
+	}
+}
+
+// Test_Severity_Unknown ensures errors with unknown levels can still be
+// rendered.
+func Test_Severity_Unknown(t *testing.T) {
+	err := Mask(&Error{Kind: "testKind", Severity: 9})
+
+	output := JSON(err)
+	if validateErr := Validate([]byte(output)); validateErr != nil {
+		t.Fatal(validateErr)
+	}
+
+	var o JSONError
+	unmarshalErr := json.Unmarshal([]byte(output), &o)
+	if unmarshalErr != nil {
+		t.Fatal(unmarshalErr)
+	}
+	if o.Severity != 9 {
+		t.Fatalf("severity = %v, want %v", o.Severity, Level(9))
+	}
+
+	goString := fmt.Sprintf("%#v", err)
+	if !strings.Contains(goString, `"severity":"Level(9)"`) {
+		t.Fatalf("expected severity in %q", goString)
+	}
+}
@@ -1,40 +1,271 @@
 package microerror
 
 import (
+	"bytes"
 	"errors"
-	"fmt"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
+// Stack returns the entries recorded while err was masked, starting with
+// the one closest to where err originated.
+func Stack(err error) []StackEntry {
+	var serr *stackedError
+	if !errors.As(err, &serr) {
+		return nil
+	}
+
+	return createStackTrace(serr)
+}
+
 func createStackTrace(err *stackedError) []StackEntry {
-	stack := []StackEntry{
-		err.stackEntry,
+	n := stackDepth(err)
+	stack := make([]StackEntry, n)
+	for s := err; s != nil; s = nextStackedError(s.underlying) {
+		n--
+		stack[n] = s.entry()
 	}
 
-	underlying := err.underlying
-	var sErr *stackedError
-	for errors.As(underlying, &sErr) {
-		stack = append([]StackEntry{sErr.stackEntry}, stack...)
-		underlying = sErr.underlying
+	for i := 1; i < len(stack); i++ {
+		p, c := stack[i-1].Goroutine, stack[i].Goroutine
+		stack[i].GoroutineHop = p != 0 && c != 0 && p != c
 	}
 
 	return stack
 }
 
-func formatStackEntry(entry StackEntry) string {
-	return fmt.Sprintf("\t%s:%d", entry.File, entry.Line)
+// stackDepth returns the number of stackedError in the chain starting with
+// err.
+func stackDepth(err *stackedError) int {
+	var n int
+	for s := err; s != nil; s = nextStackedError(s.underlying) {
+		n++
+	}
+
+	return n
+}
+
+// nextStackedError returns the first stackedError in the chain of err
+// including err itself. Wrappers like the ones created by fmt.Errorf or
+// errors.Join are skipped.
+func nextStackedError(err error) *stackedError {
+	var serr *stackedError
+	walk(err, func(e error) bool {
+		serr, _ = e.(*stackedError)
+		return serr == nil
+	})
+
+	return serr
 }
 
+// entry resolves the recorded program counter to a StackEntry.
+func (e *stackedError) entry() StackEntry {
+	if e.frame != nil {
+		entry := *e.frame
+		entry.Goroutine = e.goroutine
+		if !e.time.IsZero() {
+			t := e.time
+			entry.Time = &t
+		}
+		return entry
+	}
+
+	pcs := [1]uintptr{e.pc}
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+
+	entry := StackEntry{
+		File: frame.File,
+		Line: frame.Line,
+		PC:   frame.PC,
+
+		Goroutine: e.goroutine,
+	}
+	if !e.time.IsZero() {
+		t := e.time
+		entry.Time = &t
+	}
+
+	return entry
+}
+
+// formatStackTrace renders trace one entry per line. When entries have
+// times their offset relative to the first entry having one is appended.
 func formatStackTrace(trace []StackEntry) string {
 	var builder strings.Builder
-	builder.Grow(len(trace))
 
+	var origin *time.Time
 	for i, stack := range trace {
 		if i > 0 {
 			builder.WriteString("\n")
 		}
-		builder.WriteString(formatStackEntry(stack))
+		if stack.GoroutineHop {
+			builder.WriteString("\t(goroutine ")
+			builder.WriteString(strconv.FormatUint(trace[i-1].Goroutine, 10))
+			builder.WriteString(" -> goroutine ")
+			builder.WriteString(strconv.FormatUint(stack.Goroutine, 10))
+			builder.WriteString(")\n")
+		}
+		builder.WriteString("\t")
+		builder.WriteString(stack.File)
+		builder.WriteString(":")
+		builder.WriteString(strconv.Itoa(stack.Line))
+
+		if stack.Time != nil {
+			if origin == nil {
+				origin = stack.Time
+			}
+			builder.WriteString(" (+")
+			builder.WriteString(stack.Time.Sub(*origin).String())
+			builder.WriteString(")")
+		}
+
+		if stack.Count > 1 && stack.Cycle > 1 {
+			builder.WriteString("\n\t(previous ")
+			builder.WriteString(strconv.Itoa(stack.Cycle))
+			builder.WriteString(" frames repeated ")
+			builder.WriteString(strconv.Itoa(stack.Count))
+			builder.WriteString(" times)")
+		} else if stack.Count > 1 {
+			builder.WriteString(" (repeated ")
+			builder.WriteString(strconv.Itoa(stack.Count))
+			builder.WriteString(" times)")
+		}
 	}
 
 	return builder.String()
 }
+
+var clock func() time.Time
+
+// SetClock sets the clock used to record when errors are masked. The times
+// are included in the stack of JSON output and rendered as offsets in Pretty
+// output, which makes the latency of propagating errors across goroutines,
+// queues and retries visible. Passing nil, the default, disables recording
+// times. Tests may pass a fake clock. It is meant to be called during
+// program initialization.
+func SetClock(c func() time.Time) {
+	clock = c
+}
+
+// FrameFunc returns the file and line recorded for the masking call at the
+// return program counter pc as returned by runtime.Callers.
+type FrameFunc func(pc uintptr) (file string, line int)
+
+var frameFunc atomic.Pointer[FrameFunc]
+
+// SetFrameFunc sets the FrameFunc used to record synthetic frames instead
+// of the real file and line of masking calls and returns the previous one.
+// This keeps golden files of rendered errors stable when code moves, see
+// the microerrortest package. Passing nil, the default, records real
+// frames. It may be called while other goroutines mask errors, but it is
+// meant to be used by tests only.
+func SetFrameFunc(f FrameFunc) FrameFunc {
+	var p *FrameFunc
+	if f != nil {
+		p = &f
+	}
+
+	previous := frameFunc.Swap(p)
+	if previous == nil {
+		return nil
+	}
+
+	return *previous
+}
+
+var recordGoroutines bool
+
+// SetGoroutineRecording enables recording the ID of the goroutine masking
+// an error. JSON and Pretty output then mark where an error was passed from
+// one goroutine to another, e.g. from a worker to its caller through a
+// channel. Getting the ID requires reading the stack header of the current
+// goroutine, which makes masking considerably slower. It is disabled by
+// default and meant to be called during program initialization.
+func SetGoroutineRecording(enabled bool) {
+	recordGoroutines = enabled
+}
+
+// goroutineID parses the ID of the current goroutine from the header of its
+// stack trace, which looks like "goroutine 18 [running]:". It returns 0 when
+// the header can not be parsed.
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+
+	b := bytes.TrimPrefix(buf[:n], []byte("goroutine "))
+	i := bytes.IndexByte(b, ' ')
+	if i < 0 {
+		return 0
+	}
+
+	id, err := strconv.ParseUint(string(b[:i]), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return id
+}
+
+// maxCycleLength is the maximum number of entries of a repeated block of
+// entries detected by collapseStack.
+const maxCycleLength = 8
+
+var collapseStacks bool
+
+// SetStackCollapsing enables collapsing repeated stack entries in JSON and
+// Pretty output. This happens when the same error is masked again and again
+// at the same call site, e.g. in retry loops. Consecutive identical entries
+// are collapsed into one with a count and consecutive repetitions of up to 8
+// entries, e.g. when an error bounces between two call sites, into a single
+// cycle. Times of the first repetition are kept. It is disabled by default,
+// which keeps every entry. It is meant to be called during program
+// initialization.
+func SetStackCollapsing(enabled bool) {
+	collapseStacks = enabled
+}
+
+// collapseStack collapses consecutive repetitions of blocks of entries. The
+// last entry of a collapsed block carries the number of repetitions and the
+// block length. Blocks covering more entries are preferred, single entries
+// being preferred over longer blocks covering as many.
+func collapseStack(stack []StackEntry) []StackEntry {
+	var collapsed []StackEntry
+	for i := 0; i < len(stack); {
+		length, count := 1, 1
+		for l := 1; l <= maxCycleLength && i+2*l <= len(stack); l++ {
+			c := 1
+			for i+(c+1)*l <= len(stack) && sameEntries(stack[i:i+l], stack[i+c*l:i+(c+1)*l]) {
+				c++
+			}
+			if c > 1 && c*l > length*count {
+				length, count = l, c
+			}
+		}
+
+		collapsed = append(collapsed, stack[i:i+length]...)
+		if count > 1 {
+			last := &collapsed[len(collapsed)-1]
+			last.Count = count
+			if length > 1 {
+				last.Cycle = length
+			}
+		}
+
+		i += length * count
+	}
+
+	return collapsed
+}
+
+func sameEntries(a []StackEntry, b []StackEntry) bool {
+	for i := range a {
+		if a[i].File != b[i].File || a[i].Line != b[i].Line {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,155 @@
+package microerror
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_collapseStack(t *testing.T) {
+	a := StackEntry{File: "a.go", Line: 1}
+	b := StackEntry{File: "b.go", Line: 2}
+	c := StackEntry{File: "c.go", Line: 3}
+
+	counted := func(e StackEntry, count int, cycle int) StackEntry {
+		e.Count = count
+		e.Cycle = cycle
+		return e
+	}
+
+	testCases := []struct {
+		name          string
+		inputStack    []StackEntry
+		expectedStack []StackEntry
+	}{
+		{
+			name:          "case 0: empty",
+			inputStack:    nil,
+			expectedStack: nil,
+		},
+		{
+			name:          "case 1: no repetitions",
+			inputStack:    []StackEntry{a, b, c},
+			expectedStack: []StackEntry{a, b, c},
+		},
+		{
+			name:          "case 2: repeated entry",
+			inputStack:    []StackEntry{a, b, b, b, c},
+			expectedStack: []StackEntry{a, counted(b, 3, 0), c},
+		},
+		{
+			name:          "case 3: repeated entry is preferred over cycle",
+			inputStack:    []StackEntry{b, b, b, b},
+			expectedStack: []StackEntry{counted(b, 4, 0)},
+		},
+		{
+			name:          "case 4: cycle of two entries",
+			inputStack:    []StackEntry{c, a, b, a, b, a, b},
+			expectedStack: []StackEntry{c, a, counted(b, 3, 2)},
+		},
+		{
+			name:          "case 5: cycle with trailing partial repetition",
+			inputStack:    []StackEntry{a, b, c, a, b, c, a, b},
+			expectedStack: []StackEntry{a, b, counted(c, 2, 3), a, b},
+		},
+		{
+			name:          "case 6: repetitions after each other",
+			inputStack:    []StackEntry{a, a, b, b, b},
+			expectedStack: []StackEntry{counted(a, 2, 0), counted(b, 3, 0)},
+		},
+		{
+			name:          "case 7: same file different lines are not repetitions",
+			inputStack:    []StackEntry{a, {File: "a.go", Line: 2}},
+			expectedStack: []StackEntry{a, {File: "a.go", Line: 2}},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			stack := collapseStack(tc.inputStack)
+			if !cmp.Equal(stack, tc.expectedStack) {
+				t.Fatalf("\n\n%s\n", cmp.Diff(tc.expectedStack, stack))
+			}
+		})
+	}
+}
+
+// newTestClock returns a clock starting at 2020-01-01 and advancing by step
+// on every call.
+func newTestClock(step time.Duration) func() time.Time {
+	t := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now := t
+		t = t.Add(step)
+		return now
+	}
+}
+
+func Test_GoroutineRecording(t *testing.T) {
+	SetGoroutineRecording(true)
+	defer SetGoroutineRecording(false)
+
+	ch := make(chan error)
+	go func() {
+		err := Mask(testMicroErr)
+		ch <- Mask(err)
+	}()
+	err := Mask(<-ch)
+
+	stack := Stack(err)
+	if len(stack) != 3 {
+		t.Fatalf("expected 3 stack entries, got %d", len(stack))
+	}
+	if stack[0].Goroutine == 0 || stack[0].Goroutine != stack[1].Goroutine {
+		t.Fatalf("expected first two entries in the same goroutine, got %d and %d", stack[0].Goroutine, stack[1].Goroutine)
+	}
+	if stack[2].Goroutine == 0 || stack[2].Goroutine == stack[1].Goroutine {
+		t.Fatalf("expected last entry in another goroutine, got %d and %d", stack[1].Goroutine, stack[2].Goroutine)
+	}
+	if stack[0].GoroutineHop || stack[1].GoroutineHop || !stack[2].GoroutineHop {
+		t.Fatalf("expected only last entry to hop goroutines")
+	}
+
+	expected := "\t(goroutine " + strconv.FormatUint(stack[1].Goroutine, 10) + " -> goroutine " + strconv.FormatUint(stack[2].Goroutine, 10) + ")\n"
+	if !strings.Contains(Pretty(err, true), expected) {
+		t.Fatalf("expected %q in %q", expected, Pretty(err, true))
+	}
+}
+
+func Test_SetFrameFunc(t *testing.T) {
+	var n int
+	SetFrameFunc(func(pc uintptr) (string, int) {
+		n++
+		return "frame.go", n
+	})
+	defer SetFrameFunc(nil)
+
+	SetClock(newTestClock(time.Second))
+	defer SetClock(nil)
+
+	err := Maskf(testMicroErr, "test annotation")
+	err = Mask(err)
+
+	stack := Stack(err)
+	if len(stack) != 2 {
+		t.Fatalf("expected 2 stack entries, got %d", len(stack))
+	}
+	for i, e := range stack {
+		if e.File != "frame.go" || e.Line != i+1 || e.PC == 0 {
+			t.Fatalf("expected synthetic entry %d, got %#v", i+1, e)
+		}
+	}
+	if stack[0].Time == nil || stack[1].Time.Sub(*stack[0].Time) != time.Second {
+		t.Fatalf("expected times to be recorded with synthetic entries")
+	}
+
+	expected := "Test kind: test annotation\n\tframe.go:1 (+0s)\n\tframe.go:2 (+1s)"
+	if Pretty(err, true) != expected {
+		t.Fatalf("expected %q got %q", expected, Pretty(err, true))
+	}
+}
@@ -5,6 +5,80 @@ import (
 	"unicode"
 )
 
+// KindFormatter turns Error.Kind into the message returned by Error().
+type KindFormatter func(kind string) string
+
+var kindFormatter KindFormatter = LowerCase
+
+// NewKindFormatter returns f to be set as Error.Formatter, e.g.
+//
+//	Formatter: microerror.NewKindFormatter(microerror.KebabCase),
+//
+// Error holds a pointer to f as func fields would make it incomparable.
+func NewKindFormatter(f KindFormatter) *KindFormatter {
+	return &f
+}
+
+// SetKindFormatter sets the KindFormatter used by all errors which do not
+// set Error.Formatter. Passing nil restores the default LowerCase. It is
+// meant to be called during program initialization.
+func SetKindFormatter(f KindFormatter) {
+	if f == nil {
+		f = LowerCase
+	}
+	kindFormatter = f
+}
+
+// LowerCase formats "invalidHTTPConfigError" as "invalid http config error".
+// This is the default. Messages are the same as in previous releases, i.e.
+// separators are kept and a single upper case letter at the end is not split
+// off, e.g. "kube-apiserverB" becomes "kube-apiserverb". See LowerCaseWords
+// for splitting kinds like the other formatters do.
+func LowerCase(kind string) string {
+	return toStringCase(kind)
+}
+
+// LowerCaseWords formats "invalidHTTPConfigError" as "invalid http config
+// error" like LowerCase, but splits kinds into words like SentenceCase,
+// SnakeCase and KebabCase do, e.g. "kube-apiserverB" becomes "kube apiserver
+// b".
+func LowerCaseWords(kind string) string {
+	return joinWords(splitKind(kind), " ", strings.ToLower)
+}
+
+// SentenceCase formats "invalidHTTPConfigError" as "Invalid HTTP config
+// error". Acronyms keep their case.
+func SentenceCase(kind string) string {
+	words := splitKind(kind)
+	for i, w := range words {
+		if !isAcronym(w) {
+			words[i] = strings.ToLower(w)
+		}
+	}
+	if len(words) > 0 {
+		r := []rune(words[0])
+		r[0] = unicode.ToUpper(r[0])
+		words[0] = string(r)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// SnakeCase formats "invalidHTTPConfigError" as "invalid_http_config_error".
+func SnakeCase(kind string) string {
+	return joinWords(splitKind(kind), "_", strings.ToLower)
+}
+
+// KebabCase formats "invalidHTTPConfigError" as "invalid-http-config-error".
+func KebabCase(kind string) string {
+	return joinWords(splitKind(kind), "-", strings.ToLower)
+}
+
+// Verbatim returns kind unchanged.
+func Verbatim(kind string) string {
+	return kind
+}
+
 func toStringCase(input string) string {
 	chunks := []string{}
 	split := strings.Split(input, "")
@@ -33,3 +107,94 @@ func toStringCase(input string) string {
 
 	return strings.Join(chunks, "")
 }
+
+func joinWords(words []string, sep string, f func(string) string) string {
+	for i, w := range words {
+		words[i] = f(w)
+	}
+
+	return strings.Join(words, sep)
+}
+
+func isAcronym(word string) bool {
+	var letters int
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			if !unicode.IsUpper(r) {
+				return false
+			}
+			letters++
+		}
+	}
+
+	return letters > 1
+}
+
+// splitKind splits a camelCase kind into words. Runs of upper case letters
+// are treated as acronyms, so "invalidHTTPConfig" becomes "invalid", "HTTP"
+// and "Config". Digits stick to the word they follow, e.g. "oldV2Route"
+// becomes "old", "V2" and "Route". Spaces, underscores, dashes and dots
+// separate words and are dropped.
+func splitKind(kind string) []string {
+	runes := []rune(kind)
+
+	var words []string
+	var start int
+	flush := func(end int) {
+		if end > start {
+			words = append(words, string(runes[start:end]))
+		}
+	}
+
+	for i, r := range runes {
+		if isSeparator(r) {
+			flush(i)
+			start = i + 1
+			continue
+		}
+		if i == start || !unicode.IsUpper(r) {
+			continue
+		}
+
+		p := runes[i-1]
+		var n rune
+		if i+1 < len(runes) {
+			n = runes[i+1]
+		}
+
+		// "fooBar" and "v2Route" split before the upper case letter.
+		// "HTTPConfig" splits before the last upper case letter of the
+		// acronym.
+		if !unicode.IsUpper(p) || unicode.IsLower(n) {
+			flush(i)
+			start = i
+		}
+	}
+	flush(len(runes))
+
+	return words
+}
+
+func isSeparator(r rune) bool {
+	return r == ' ' || r == '_' || r == '-' || r == '.'
+}
+
+// KindFromMessage is the inverse of LowerCase. It turns a message like
+// "invalid http config error" into the kind "invalidHttpConfigError". Since
+// LowerCase loses the case of acronyms the result may differ from the
+// original kind. Use Registry.ParseMessage to map messages back to known
+// errors.
+func KindFromMessage(message string) string {
+	var builder strings.Builder
+	builder.Grow(len(message))
+
+	for i, w := range strings.Fields(message) {
+		r := []rune(w)
+		if i > 0 {
+			r[0] = unicode.ToUpper(r[0])
+		}
+		builder.WriteString(string(r))
+	}
+
+	return builder.String()
+}
@@ -1,6 +1,7 @@
 package microerror
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -75,6 +76,21 @@ func Test_toStringCase(t *testing.T) {
 			InputString:    "statusCode200",
 			ExpectedString: "status code200",
 		},
+		{
+			Name:           "case 13: single upper case letter at the end is not split",
+			InputString:    "fooB",
+			ExpectedString: "foob",
+		},
+		{
+			Name:           "case 14: dashes are kept",
+			InputString:    "kube-apiserverNotReady",
+			ExpectedString: "kube-apiserver not ready",
+		},
+		{
+			Name:           "case 15: underscores are kept",
+			InputString:    "foo_bar",
+			ExpectedString: "foo_bar",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -86,3 +102,242 @@ func Test_toStringCase(t *testing.T) {
 		})
 	}
 }
+
+func Test_splitKind(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		InputString   string
+		ExpectedWords []string
+	}{
+		{
+			Name:          "case 0: empty",
+			InputString:   "",
+			ExpectedWords: nil,
+		},
+		{
+			Name:          "case 1: single word",
+			InputString:   "foo",
+			ExpectedWords: []string{"foo"},
+		},
+		{
+			Name:          "case 2: camel case",
+			InputString:   "fooBarBaz",
+			ExpectedWords: []string{"foo", "Bar", "Baz"},
+		},
+		{
+			Name:          "case 3: acronym in the middle",
+			InputString:   "invalidHTTPConfig",
+			ExpectedWords: []string{"invalid", "HTTP", "Config"},
+		},
+		{
+			Name:          "case 4: acronym at the start",
+			InputString:   "HTTPConfig",
+			ExpectedWords: []string{"HTTP", "Config"},
+		},
+		{
+			Name:          "case 5: acronym at the end",
+			InputString:   "invalidHTTP",
+			ExpectedWords: []string{"invalid", "HTTP"},
+		},
+		{
+			Name:          "case 6: consecutive acronyms are not separable",
+			InputString:   "invalidHTTPAPIConfig",
+			ExpectedWords: []string{"invalid", "HTTPAPI", "Config"},
+		},
+		{
+			Name:          "case 7: single upper case letter at the end",
+			InputString:   "fooB",
+			ExpectedWords: []string{"foo", "B"},
+		},
+		{
+			Name:          "case 8: single upper case letter word",
+			InputString:   "aLongWay",
+			ExpectedWords: []string{"a", "Long", "Way"},
+		},
+		{
+			Name:          "case 9: digits stick to the preceding word",
+			InputString:   "oldV2RouteNotReachable",
+			ExpectedWords: []string{"old", "V2", "Route", "Not", "Reachable"},
+		},
+		{
+			Name:          "case 10: non-ASCII runes",
+			InputString:   "ungültigeÄnderungFehler",
+			ExpectedWords: []string{"ungültige", "Änderung", "Fehler"},
+		},
+		{
+			Name:          "case 11: separators",
+			InputString:   "foo_bar-baz.qux quux",
+			ExpectedWords: []string{"foo", "bar", "baz", "qux", "quux"},
+		},
+		{
+			Name:          "case 12: repeated separators",
+			InputString:   "__foo__Bar",
+			ExpectedWords: []string{"foo", "Bar"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			words := splitKind(tc.InputString)
+			if !reflect.DeepEqual(words, tc.ExpectedWords) {
+				t.Fatalf("expected %#v got %#v", tc.ExpectedWords, words)
+			}
+		})
+	}
+}
+
+func Test_KindFormatter(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Formatter      KindFormatter
+		InputString    string
+		ExpectedString string
+	}{
+		{
+			Name:           "case 0: lower case",
+			Formatter:      LowerCase,
+			InputString:    "invalidHTTPConfigError",
+			ExpectedString: "invalid http config error",
+		},
+		{
+			Name:           "case 1: sentence case",
+			Formatter:      SentenceCase,
+			InputString:    "invalidHTTPConfigError",
+			ExpectedString: "Invalid HTTP config error",
+		},
+		{
+			Name:           "case 2: sentence case with acronym at the start",
+			Formatter:      SentenceCase,
+			InputString:    "APINotAvailableError",
+			ExpectedString: "API not available error",
+		},
+		{
+			Name:           "case 3: sentence case with non-ASCII start",
+			Formatter:      SentenceCase,
+			InputString:    "übelFehler",
+			ExpectedString: "Übel fehler",
+		},
+		{
+			Name:           "case 4: snake case",
+			Formatter:      SnakeCase,
+			InputString:    "invalidHTTPConfigError",
+			ExpectedString: "invalid_http_config_error",
+		},
+		{
+			Name:           "case 5: kebab case",
+			Formatter:      KebabCase,
+			InputString:    "invalidHTTPConfigError",
+			ExpectedString: "invalid-http-config-error",
+		},
+		{
+			Name:           "case 6: verbatim",
+			Formatter:      Verbatim,
+			InputString:    "invalidHTTPConfigError",
+			ExpectedString: "invalidHTTPConfigError",
+		},
+		{
+			Name:           "case 7: sentence case empty",
+			Formatter:      SentenceCase,
+			InputString:    "",
+			ExpectedString: "",
+		},
+		{
+			Name:           "case 8: lower case keeps separators",
+			Formatter:      LowerCase,
+			InputString:    "kube-apiserver_fooB",
+			ExpectedString: "kube-apiserver_foob",
+		},
+		{
+			Name:           "case 9: lower case words",
+			Formatter:      LowerCaseWords,
+			InputString:    "invalidHTTPConfigError",
+			ExpectedString: "invalid http config error",
+		},
+		{
+			Name:           "case 10: lower case words splits separators",
+			Formatter:      LowerCaseWords,
+			InputString:    "kube-apiserver_fooB",
+			ExpectedString: "kube apiserver foo b",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			output := tc.Formatter(tc.InputString)
+			if output != tc.ExpectedString {
+				t.Fatalf("expected %#v got %#v", tc.ExpectedString, output)
+			}
+		})
+	}
+}
+
+func Test_Error_Formatter(t *testing.T) {
+	defer SetKindFormatter(nil)
+
+	perError := &Error{
+		Kind:      "invalidHTTPConfigError",
+		Formatter: NewKindFormatter(KebabCase),
+	}
+	global := &Error{
+		Kind: "invalidHTTPConfigError",
+	}
+
+	if global.Error() != "invalid http config error" {
+		t.Fatalf("expected %#v got %#v", "invalid http config error", global.Error())
+	}
+
+	SetKindFormatter(SnakeCase)
+
+	if global.Error() != "invalid_http_config_error" {
+		t.Fatalf("expected %#v got %#v", "invalid_http_config_error", global.Error())
+	}
+	if perError.Error() != "invalid-http-config-error" {
+		t.Fatalf("expected %#v got %#v", "invalid-http-config-error", perError.Error())
+	}
+	if Maskf(global, "test annotation").Error() != "invalid_http_config_error: test annotation" {
+		t.Fatalf("expected %#v got %#v", "invalid_http_config_error: test annotation", Maskf(global, "test annotation").Error())
+	}
+}
+
+func Test_KindFromMessage(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		InputString    string
+		ExpectedString string
+	}{
+		{
+			Name:           "case 0: single word",
+			InputString:    "foo",
+			ExpectedString: "foo",
+		},
+		{
+			Name:           "case 1: multiple words",
+			InputString:    "foo bar baz",
+			ExpectedString: "fooBarBaz",
+		},
+		{
+			Name:           "case 2: acronyms are not restored",
+			InputString:    toStringCase("invalidHTTPConfigError"),
+			ExpectedString: "invalidHttpConfigError",
+		},
+		{
+			Name:           "case 3: round trip without acronyms",
+			InputString:    toStringCase("authenticationError"),
+			ExpectedString: "authenticationError",
+		},
+		{
+			Name:           "case 4: non-ASCII runes",
+			InputString:    "ungültige änderung",
+			ExpectedString: "ungültigeÄnderung",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			output := KindFromMessage(tc.InputString)
+			if output != tc.ExpectedString {
+				t.Fatalf("expected %#v got %#v", tc.ExpectedString, output)
+			}
+		})
+	}
+}
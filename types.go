@@ -2,8 +2,8 @@ package microerror
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
+	"time"
 )
 
 const (
@@ -12,9 +12,29 @@ const (
 )
 
 type Error struct {
+	// Code is an optional stable identifier like "GS-1042" which, unlike
+	// Kind, does not change when errors are renamed. See Registry for
+	// ensuring codes are unique.
+	Code string `json:"code,omitempty"`
 	Desc string `json:"desc,omitempty"`
 	Docs string `json:"docs,omitempty"`
 	Kind string `json:"kind"`
+	// Severity is the severity of errors of this kind, see the Severity
+	// function.
+	Severity Level `json:"severity,omitempty"`
+	// Categories group errors across kinds, see IsCategory and
+	// NewCategories. Categories of ancestors are inherited.
+	Categories *Categories `json:"categories,omitempty"`
+
+	// Parent is the more general kind of this error. Errors match their
+	// ancestors in errors.Is, e.g. clusterNotFoundError matches
+	// notFoundError when the latter is its parent.
+	Parent *Error `json:"-"`
+
+	// Formatter turns Kind into the message returned by Error(), see
+	// NewKindFormatter. When nil the KindFormatter set with
+	// SetKindFormatter is used.
+	Formatter *KindFormatter `json:"-"`
 }
 
 // GoString is here for backward compatibility.
@@ -25,13 +45,62 @@ func (e *Error) GoString() string {
 }
 
 func (e *Error) Error() string {
-	return toStringCase(e.Kind)
+	if codesInMessages && e.Code != "" {
+		return codePrefix(e.Code) + e.message()
+	}
+	return e.message()
+}
+
+// Is reports whether target is an ancestor of e. It is called by errors.Is
+// after comparing e with target itself.
+func (e *Error) Is(target error) bool {
+	var found bool
+	walkAncestors(e, func(p *Error) bool {
+		found = p != e && p == target
+		return !found
+	})
+
+	return found
+}
+
+// message formats Kind without the code.
+func (e *Error) message() string {
+	if e.Formatter != nil {
+		return (*e.Formatter)(e.Kind)
+	}
+	return kindFormatter(e.Kind)
 }
 
 type JSONError struct {
 	*Error `json:",inline"`
 
+	// SchemaVersion is the JSONFormat of the document. It is not set for
+	// nested causes.
+	SchemaVersion JSONFormat `json:"schema_version,omitempty"`
+
+	// Ancestry holds the kinds of all ancestors of Error, parent first.
+	Ancestry   []string          `json:"ancestry,omitempty"`
+	Annotation string            `json:"annotation,omitempty"`
+	Fields     map[string]string `json:"fields,omitempty"`
+	Stack      []StackEntry      `json:"stack,omitempty"`
+
+	// Cause is the error set with Wrapf. It is only set in JSONFormatV1
+	// as JSONFormatV2 includes it in Causes.
+	Cause *JSONError `json:"cause,omitempty"`
+	// Causes holds all distinct layers of the error chain, outermost
+	// first. It is only set starting with JSONFormatV2.
+	Causes []JSONCause `json:"causes,omitempty"`
+}
+
+// JSONCause is a single layer of an error chain, see Layers. Layers created
+// with this package have the fields of their Error and an annotation. Other
+// layers have their message.
+type JSONCause struct {
+	*Error `json:",inline"`
+
 	Annotation string       `json:"annotation,omitempty"`
+	Message    string       `json:"message,omitempty"`
+	Type       string       `json:"type"`
 	Stack      []StackEntry `json:"stack,omitempty"`
 }
 
@@ -39,11 +108,37 @@ type StackEntry struct {
 	File string  `json:"file"`
 	Line int     `json:"line"`
 	PC   uintptr `json:"-"`
+	// Time is when the entry was recorded. It is only set when a clock is
+	// configured with SetClock.
+	Time *time.Time `json:"time,omitempty"`
+	// Goroutine is the ID of the goroutine the entry was recorded in. It is
+	// only set when enabled with SetGoroutineRecording. GoroutineHop is set
+	// when it differs from the goroutine of the previous entry, i.e. the
+	// error was passed to another goroutine in between.
+	Goroutine    uint64 `json:"goroutine,omitempty"`
+	GoroutineHop bool   `json:"goroutine_hop,omitempty"`
+
+	// Count is the number of consecutive repetitions of this entry, or of
+	// the last Cycle entries ending with this one, when repeated entries
+	// are collapsed. See SetStackCollapsing.
+	Count int `json:"count,omitempty"`
+	Cycle int `json:"cycle,omitempty"`
 }
 
 type annotatedError struct {
 	annotation string
 	underlying *Error
+
+	// format is the format annotation was rendered from and args are its
+	// arguments rendered with their verbs, see renderArgs. They are only
+	// set while a Catalog is set with SetCatalog so the annotation can be
+	// rendered again with a translated format, see Localize. The arguments
+	// themselves are never retained.
+	format string
+	args   []string
+
+	// cause is the error underlying was caused by, see Wrapf.
+	cause error
 }
 
 // GoString is here for backward compatibility.
@@ -54,18 +149,18 @@ func (e *annotatedError) GoString() string {
 }
 
 func (e *annotatedError) Error() string {
-	if e.annotation == "" {
-		return e.underlying.Error()
+	msg := e.underlying.Error()
+	if e.annotation != "" {
+		msg += delimiter + e.annotation
 	}
-	return e.underlying.Error() + ": " + e.annotation
+	if e.cause != nil {
+		msg += delimiter + e.cause.Error()
+	}
+	return msg
 }
 
 func (e *annotatedError) MarshalJSON() ([]byte, error) {
-	o := JSONError{
-		Error: e.underlying,
-
-		Annotation: e.annotation,
-	}
+	o := newJSONError(e)
 
 	bytes, err := json.Marshal(o)
 	if err != nil {
@@ -79,9 +174,38 @@ func (e *annotatedError) Unwrap() error {
 	return e.underlying
 }
 
+// wrappedError is the annotatedError created by Wrapf. Only it unwraps to
+// the cause as well, so errors created with Maskf keep unwrapping to a
+// single error.
+type wrappedError struct {
+	*annotatedError
+}
+
+// Unwrap returns the annotatedError and its cause, so errors.Is and
+// errors.As match both.
+func (e *wrappedError) Unwrap() []error {
+	return []error{e.annotatedError, e.cause}
+}
+
 type stackedError struct {
-	stackEntry StackEntry
+	// pc is the return program counter of the masking call as returned by
+	// runtime.Callers.
+	pc         uintptr
 	underlying error
+
+	// severity overrides the severity of underlying, see
+	// MaskWithSeverity.
+	severity Level
+	// fields are the values extracted from context.Context, see MaskCtx.
+	fields map[string]string
+	// time is when the error was masked, see SetClock.
+	time time.Time
+	// goroutine is the ID of the goroutine masking the error, see
+	// SetGoroutineRecording.
+	goroutine uint64
+	// frame is the synthetic frame recorded instead of the one pc
+	// resolves to, see SetFrameFunc.
+	frame *StackEntry
 }
 
 // GoString is here for backward compatibility.
@@ -95,37 +219,8 @@ func (e *stackedError) Error() string {
 	return e.underlying.Error()
 }
 
-// MarshalJSON unwraps all the stackedError and reconstructs the stack. Then it
-// tries to find annotatedError to find the custom error annotation and finally
-// tries to find Error or just creates one from arbitrary error. Then it sets
-// all the fields to JSONError and finally marshals it using standard
-// json.Marshal call.
 func (e *stackedError) MarshalJSON() ([]byte, error) {
-	stack := createStackTrace(e)
-
-	var eerr *Error
-	var annotation string
-	{
-		if errors.As(e, &eerr) {
-			var aerr *annotatedError
-			if errors.As(e, &aerr) {
-				annotation = aerr.annotation
-			}
-		} else {
-			eerr = &Error{
-				Kind: kindUnknown,
-			}
-
-			annotation = e.Error()
-		}
-	}
-
-	o := JSONError{
-		Error: eerr,
-
-		Annotation: annotation,
-		Stack:      stack,
-	}
+	o := newJSONError(e)
 
 	bytes, err := json.Marshal(o)
 	if err != nil {
@@ -135,12 +230,15 @@ func (e *stackedError) MarshalJSON() ([]byte, error) {
 	return bytes, nil
 }
 
+// StackTrace returns the return program counters of all masking calls in
+// the format of runtime.Callers, most recent call first. It is used by
+// Sentry and other tools extracting stack traces from errors.
 func (e *stackedError) StackTrace() []uintptr {
-	stack := createStackTrace(e)
-
-	var pcs []uintptr
-	for _, e := range stack {
-		pcs = append(pcs, e.PC)
+	n := stackDepth(e)
+	pcs := make([]uintptr, n)
+	for s := e; s != nil; s = nextStackedError(s.underlying) {
+		n--
+		pcs[n] = s.pc
 	}
 
 	return pcs